@@ -0,0 +1,181 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"netpala/common"
+	"netpala/dbus"
+	"netpala/network"
+	"os"
+	"strings"
+
+	godbus "github.com/godbus/dbus/v5"
+)
+
+const usage = `usage: netpala [command]
+
+Without a command netpala starts the interactive interface.
+
+commands:
+  qr <ssid> [-png file]        print a Wi-Fi QR code for a saved network
+  import <file>                add a connection from a .nmconnection keyfile
+  vpn import <file>            add a VPN from a wg-quick .conf or OpenVPN .ovpn file
+  export <name> [-o dir]       write a saved connection as a .nmconnection keyfile
+`
+
+// runCLI handles the non-interactive subcommands and returns the exit code.
+func runCLI(args []string) int {
+	switch args[0] {
+	case "qr":
+		return runQR(args[1:])
+	case "import":
+		return runImport(args[1:])
+	case "export":
+		return runExport(args[1:])
+	case "vpn":
+		if len(args) > 1 && args[1] == "import" {
+			return runImport(args[2:])
+		}
+		fmt.Fprint(os.Stderr, "usage: netpala vpn import <file>\n")
+		return 2
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command '%s'\n\n%s", args[0], usage)
+		return 2
+	}
+}
+
+func runQR(args []string) int {
+	fs := flag.NewFlagSet("qr", flag.ContinueOnError)
+	pngPath := fs.String("png", "", "write the QR code to a PNG file instead of the terminal")
+	scale := fs.Int("scale", 8, "pixels per module for -png")
+	if err := fs.Parse(reorderFlags(args)); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprint(os.Stderr, "usage: netpala qr <ssid> [-png file]\n")
+		return 2
+	}
+
+	conn, err := godbus.SystemBus()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to D-Bus: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+
+	path, _, err := network.FindConnectionBySSID(conn, fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	_, payload, err := network.GetWifiQRPayload(conn, path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	modules, err := common.EncodeQR(payload)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if *pngPath != "" {
+		if err := common.WriteQRPNG(modules, *pngPath, *scale); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write %s: %v\n", *pngPath, err)
+			return 1
+		}
+		return 0
+	}
+	fmt.Println(common.RenderQR(modules))
+	return 0
+}
+
+func runImport(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, "usage: netpala import <file>\n")
+		return 2
+	}
+
+	conn, err := godbus.SystemBus()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to D-Bus: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+
+	id, warnings, err := dbus.ImportConnection(conn, args[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: ignored unsupported directive %s\n", w)
+	}
+	fmt.Printf("Imported '%s'\n", id)
+	return 0
+}
+
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	dir := fs.String("o", ".", "directory to write the keyfile to, or - for stdout")
+	if err := fs.Parse(reorderFlags(args)); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprint(os.Stderr, "usage: netpala export <name> [-o dir]\n")
+		return 2
+	}
+
+	conn, err := godbus.SystemBus()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to D-Bus: %v\n", err)
+		return 1
+	}
+	defer conn.Close()
+
+	path, err := network.FindConnectionByID(conn, fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if *dir == "-" {
+		_, data, err := network.ExportKeyfile(conn, path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Println(data)
+		return 0
+	}
+
+	switch msg := dbus.ExportConnectionCmd(conn, path, *dir)().(type) {
+	case common.ErrMsg:
+		fmt.Fprintln(os.Stderr, msg.Err)
+		return 1
+	case common.NoticeMsg:
+		fmt.Println(msg)
+	}
+	return 0
+}
+
+// reorderFlags moves flags in front of positional arguments so that
+// `netpala qr MyWifi -png out.png` works as well as the flag-first form.
+func reorderFlags(args []string) []string {
+	var flags, positional []string
+	for i := 0; i < len(args); i++ {
+		if len(args[i]) > 1 && args[i][0] == '-' {
+			flags = append(flags, args[i])
+			if i+1 < len(args) && !strings.Contains(args[i], "=") {
+				flags = append(flags, args[i+1])
+				i++
+			}
+			continue
+		}
+		positional = append(positional, args[i])
+	}
+	return append(flags, positional...)
+}
@@ -0,0 +1,236 @@
+package common
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// IsPKCS12 reports whether a file looks like a PKCS#12 (.p12/.pfx) bundle.
+func IsPKCS12(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".p12" || ext == ".pfx" {
+		return true
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	_, err = parsePFX(data)
+	return err == nil
+}
+
+// ValidateCertificate checks that path holds an X.509 certificate in PEM or
+// DER form.
+func ValidateCertificate(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if block, _ := pem.Decode(data); block != nil {
+		if block.Type != "CERTIFICATE" {
+			return fmt.Errorf("%s: expected a CERTIFICATE, found %s", path, block.Type)
+		}
+		data = block.Bytes
+	}
+	if _, err := x509.ParseCertificate(data); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// ValidatePrivateKey checks that path holds a private key and, for keys
+// encrypted in a way the standard library understands, that the password
+// unlocks it.
+func ValidatePrivateKey(path, password string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		if _, err := x509.ParsePKCS8PrivateKey(data); err != nil {
+			return fmt.Errorf("%s: not a PEM or DER private key", path)
+		}
+		return nil
+	}
+
+	switch {
+	case block.Type == "ENCRYPTED PRIVATE KEY":
+		// PKCS#8 PBES2; wpa_supplicant decrypts it, we can only check there is a password.
+		if password == "" {
+			return fmt.Errorf("%s is encrypted, a key password is required", path)
+		}
+		return nil
+	case x509.IsEncryptedPEMBlock(block): // legacy OpenSSL encryption, still common
+		if password == "" {
+			return fmt.Errorf("%s is encrypted, a key password is required", path)
+		}
+		der, err := x509.DecryptPEMBlock(block, []byte(password))
+		if err != nil {
+			return fmt.Errorf("%s: wrong key password", path)
+		}
+		block.Bytes = der
+	case !strings.HasSuffix(block.Type, "PRIVATE KEY"):
+		return fmt.Errorf("%s: expected a PRIVATE KEY, found %s", path, block.Type)
+	}
+
+	if _, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		return nil
+	}
+	if _, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return nil
+	}
+	if _, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: unsupported private key format", path)
+}
+
+// ValidatePKCS12 checks that path holds a PKCS#12 bundle and, when the bundle
+// is integrity protected with a password based MAC, that password opens it.
+func ValidatePKCS12(path, password string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	pfx, err := parsePFX(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if len(pfx.MacData.Mac.Digest) == 0 {
+		return nil // no MAC to check
+	}
+
+	var content []byte
+	if _, err := asn1.Unmarshal(pfx.AuthSafe.Content.Bytes, &content); err != nil {
+		return fmt.Errorf("%s: malformed PKCS#12 content", path)
+	}
+
+	var newHash func() hash.Hash
+	switch {
+	case pfx.MacData.Mac.Algorithm.Algorithm.Equal(oidSHA1):
+		newHash = sha1.New
+	case pfx.MacData.Mac.Algorithm.Algorithm.Equal(oidSHA256):
+		newHash = sha256.New
+	default:
+		return nil // unknown MAC, leave it to wpa_supplicant
+	}
+
+	iterations := pfx.MacData.Iterations
+	if iterations == 0 {
+		iterations = 1
+	}
+	key := pkcs12KDF(newHash, 3, bmpString(password), pfx.MacData.MacSalt, iterations, newHash().Size())
+	mac := hmac.New(newHash, key)
+	mac.Write(content)
+	if !hmac.Equal(mac.Sum(nil), pfx.MacData.Mac.Digest) {
+		return fmt.Errorf("%s: wrong password for PKCS#12 bundle", path)
+	}
+	return nil
+}
+
+var (
+	oidSHA1   = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidData   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+)
+
+// RFC 7292 PFX structure, enough of it to check the MAC.
+type pfxPdu struct {
+	Version  int
+	AuthSafe struct {
+		ContentType asn1.ObjectIdentifier
+		Content     asn1.RawValue `asn1:"tag:0,explicit,optional"`
+	}
+	MacData struct {
+		Mac struct {
+			Algorithm struct {
+				Algorithm  asn1.ObjectIdentifier
+				Parameters asn1.RawValue `asn1:"optional"`
+			}
+			Digest []byte
+		}
+		MacSalt    []byte
+		Iterations int `asn1:"optional,default:1"`
+	} `asn1:"optional"`
+}
+
+func parsePFX(data []byte) (pfxPdu, error) {
+	var pfx pfxPdu
+	rest, err := asn1.Unmarshal(data, &pfx)
+	if err != nil || len(rest) != 0 {
+		return pfx, fmt.Errorf("not a PKCS#12 bundle")
+	}
+	if pfx.Version != 3 {
+		return pfx, fmt.Errorf("unsupported PKCS#12 version %d", pfx.Version)
+	}
+	if !pfx.AuthSafe.ContentType.Equal(oidData) {
+		return pfx, fmt.Errorf("public key protected PKCS#12 bundles are not supported")
+	}
+	return pfx, nil
+}
+
+// bmpString encodes a password the way PKCS#12 expects: UTF-16BE with a
+// trailing NUL character.
+func bmpString(s string) []byte {
+	var out []byte
+	for _, r := range utf16.Encode([]rune(s)) {
+		out = append(out, byte(r>>8), byte(r))
+	}
+	return append(out, 0, 0)
+}
+
+// pkcs12KDF implements the key derivation from RFC 7292 appendix B.2.
+func pkcs12KDF(newHash func() hash.Hash, id byte, password, salt []byte, iterations, size int) []byte {
+	const v = 64 // block size of SHA-1 and SHA-256
+	fill := func(src []byte) []byte {
+		if len(src) == 0 {
+			return nil
+		}
+		out := make([]byte, v*((len(src)+v-1)/v))
+		for i := range out {
+			out[i] = src[i%len(src)]
+		}
+		return out
+	}
+
+	D := bytes.Repeat([]byte{id}, v)
+	I := append(fill(salt), fill(password)...)
+
+	var out []byte
+	for len(out) < size {
+		h := newHash()
+		h.Write(D)
+		h.Write(I)
+		A := h.Sum(nil)
+		for i := 1; i < iterations; i++ {
+			h.Reset()
+			h.Write(A)
+			A = h.Sum(A[:0])
+		}
+		out = append(out, A...)
+
+		// I_j = (I_j + B + 1) mod 2^(v*8) for every v-byte block of I.
+		B := fill(A)[:v]
+		for j := 0; j < len(I); j += v {
+			carry := 1
+			for k := v - 1; k >= 0; k-- {
+				sum := int(I[j+k]) + int(B[k]) + carry
+				I[j+k] = byte(sum)
+				carry = sum >> 8
+			}
+		}
+	}
+	return out[:size]
+}
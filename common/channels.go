@@ -0,0 +1,151 @@
+package common
+
+import "sort"
+
+// Bands in the order the analyzer shows them.
+var Bands = []string{"2.4 GHz", "5 GHz", "6 GHz"}
+
+// ChannelUsage is how busy one 20 MHz channel is.
+type ChannelUsage struct {
+	Channel int
+	Freq    int
+	APs     int     // access points overlapping the channel
+	Load    float64 // sum of their signal strengths (0-1) times the overlap
+	Current bool    // the channel the device is connected on
+}
+
+// ChannelToFreq converts a channel number in a band to its centre frequency
+// in MHz.
+func ChannelToFreq(band string, channel int) int {
+	switch band {
+	case "2.4 GHz":
+		if channel == 14 {
+			return 2484
+		}
+		return 2407 + 5*channel
+	case "5 GHz":
+		if channel >= 182 {
+			return 4000 + 5*channel
+		}
+		return 5000 + 5*channel
+	case "6 GHz":
+		if channel == 2 {
+			return 5935
+		}
+		return 5950 + 5*channel
+	}
+	return 0
+}
+
+// bandChannels lists the channels the analyzer charts for a band. 6 GHz has
+// 59 of them, so only the preferred scanning channels (PSC) are listed;
+// occupied ones are added by AnalyzeChannels.
+func bandChannels(band string) []int {
+	var channels []int
+	switch band {
+	case "2.4 GHz":
+		for ch := 1; ch <= 13; ch++ {
+			channels = append(channels, ch)
+		}
+	case "5 GHz":
+		for _, r := range [][2]int{{36, 64}, {100, 144}, {149, 165}} {
+			for ch := r[0]; ch <= r[1]; ch += 4 {
+				channels = append(channels, ch)
+			}
+		}
+	case "6 GHz":
+		for ch := 5; ch <= 229; ch += 16 {
+			channels = append(channels, ch)
+		}
+	}
+	return channels
+}
+
+// apSpan returns the frequency range an access point transmits on. On 5 and
+// 6 GHz wide channels are fixed blocks of 20 MHz channels; on 2.4 GHz the
+// side of a 40 MHz secondary channel isn't known, so it's spread evenly.
+func apSpan(ap AccessPoint) (int, int) {
+	width := int(ap.Bandwidth)
+	if width < 20 {
+		width = 20
+	}
+	band := FreqToBand(ap.Frequency)
+	channel := FreqToChannel(ap.Frequency)
+	wide := band == "6 GHz" || (band == "5 GHz" && channel >= 36 && channel <= 177)
+	if wide && width > 20 && channel > 0 {
+		first := 36
+		switch {
+		case band == "6 GHz":
+			first = 1
+		case channel >= 149:
+			// UNII-3 blocks are aligned to 149, not to 36
+			first = 149
+		}
+		per := width / 20
+		idx := (channel - first) / 4
+		start := ChannelToFreq(band, first+(idx/per)*per*4) - 10
+		return start, start + width
+	}
+	return ap.Frequency - width/2, ap.Frequency + width/2
+}
+
+// AnalyzeChannels works out how crowded each channel of each band is.
+func AnalyzeChannels(aps []AccessPoint, currentFreq int) map[string][]ChannelUsage {
+	usage := map[string][]ChannelUsage{}
+	for _, band := range Bands {
+		channels := map[int]bool{}
+		for _, ch := range bandChannels(band) {
+			channels[ch] = true
+		}
+		for _, ap := range aps {
+			if FreqToBand(ap.Frequency) == band {
+				channels[FreqToChannel(ap.Frequency)] = true
+			}
+		}
+		delete(channels, 0)
+
+		var list []ChannelUsage
+		for ch := range channels {
+			freq := ChannelToFreq(band, ch)
+			u := ChannelUsage{Channel: ch, Freq: freq, Current: freq == currentFreq}
+			for _, ap := range aps {
+				lo, hi := apSpan(ap)
+				overlap := min(hi, freq+10) - max(lo, freq-10)
+				if overlap <= 0 {
+					continue
+				}
+				u.APs++
+				u.Load += float64(ap.Signal) / 100 * float64(overlap) / 20
+			}
+			list = append(list, u)
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Channel < list[j].Channel })
+		usage[band] = list
+	}
+	return usage
+}
+
+// SuggestHotspotChannel picks the least loaded channel a hotspot can use in
+// a band ("bg" or "a"): 1, 6 or 11 on 2.4 GHz so it doesn't straddle two
+// networks, and non-DFS channels on 5 GHz since radar detection stops many
+// drivers from starting an access point there.
+func SuggestHotspotChannel(usage map[string][]ChannelUsage, band string) int {
+	candidates := map[int]bool{}
+	key := "2.4 GHz"
+	if band == "a" {
+		key = "5 GHz"
+		for _, ch := range []int{36, 40, 44, 48, 149, 153, 157, 161, 165} {
+			candidates[ch] = true
+		}
+	} else {
+		candidates = map[int]bool{1: true, 6: true, 11: true}
+	}
+
+	best, bestLoad := 0, 0.0
+	for _, u := range usage[key] {
+		if candidates[u.Channel] && (best == 0 || u.Load < bestLoad) {
+			best, bestLoad = u.Channel, u.Load
+		}
+	}
+	return best
+}
@@ -0,0 +1,62 @@
+package common
+
+import "fmt"
+
+// NM_CONNECTIVITY_* values
+const (
+	ConnectivityUnknown uint32 = 0
+	ConnectivityNone    uint32 = 1
+	ConnectivityPortal  uint32 = 2
+	ConnectivityLimited uint32 = 3
+	ConnectivityFull    uint32 = 4
+)
+
+// ConnectivityStatus is NetworkManager's view of whether we are online.
+type ConnectivityStatus struct {
+	State          uint32 // NM_STATE_*
+	Connectivity   uint32 // NM_CONNECTIVITY_*
+	CheckAvailable bool   // a connectivity check URI is configured
+	CheckEnabled   bool
+	CheckURI       string // the probe URL, which a portal redirects
+}
+
+func ConnectivityString(c uint32) string {
+	switch c {
+	case ConnectivityNone:
+		return "none"
+	case ConnectivityPortal:
+		return "captive portal"
+	case ConnectivityLimited:
+		return "limited"
+	case ConnectivityFull:
+		return "full"
+	}
+	return "unknown"
+}
+
+// NMStateString describes an NM_STATE_* value.
+func NMStateString(state uint32) string {
+	switch state {
+	case 10:
+		return "asleep"
+	case 20:
+		return "disconnected"
+	case 30:
+		return "disconnecting"
+	case 40:
+		return "connecting"
+	case 50:
+		return "connected (local only)"
+	case 60:
+		return "connected (site only)"
+	case 70:
+		return "connected"
+	}
+	return "unknown"
+}
+
+// Hyperlink wraps text in an OSC 8 escape so terminals that support it make
+// it clickable.
+func Hyperlink(url, text string) string {
+	return fmt.Sprintf("\x1b]8;;%s\x1b\\%s\x1b]8;;\x1b\\", url, text)
+}
@@ -0,0 +1,129 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// NMDeviceState values the history cares about
+const (
+	DeviceStateDisconnected uint32 = 30
+	DeviceStatePrepare      uint32 = 40
+	DeviceStateActivated    uint32 = 100
+	DeviceStateDeactivating uint32 = 110
+	DeviceStateFailed       uint32 = 120
+)
+
+// NMDeviceStateReason, the common ones
+var deviceStateReasons = map[uint32]string{
+	0:  "unknown reason",
+	1:  "no reason given",
+	7:  "secrets were required but not provided",
+	8:  "the supplicant disconnected",
+	9:  "configuring the supplicant failed",
+	10: "the supplicant failed",
+	11: "the supplicant timed out",
+	16: "DHCP failed to start",
+	17: "DHCP failed",
+	18: "DHCP timed out",
+	36: "disconnected by user",
+	38: "the device was removed",
+	39: "NetworkManager went to sleep",
+	40: "the connection was removed",
+	42: "the carrier was lost",
+	53: "the network could not be found",
+	60: "a new connection took over",
+	62: "the connection was deactivated",
+}
+
+// DeviceStateReason explains why a device changed state.
+func DeviceStateReason(reason uint32) string {
+	if s, ok := deviceStateReasons[reason]; ok {
+		return s
+	}
+	return fmt.Sprintf("reason %d", reason)
+}
+
+// HistoryEntry is one line of the connection history file.
+type HistoryEntry struct {
+	Time     time.Time `json:"time"`
+	Event    string    `json:"event"` // "connected", "disconnected" or "failed"
+	SSID     string    `json:"ssid,omitempty"`
+	BSSID    string    `json:"bssid,omitempty"`
+	Band     string    `json:"band,omitempty"`
+	Duration int64     `json:"duration,omitempty"` // seconds, for disconnects; 0 when unknown
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// NetworkStats sums up the history of one network.
+type NetworkStats struct {
+	SSID       string
+	Connects   int
+	Failures   int
+	Sessions   int           // disconnects with a known duration
+	AvgSession time.Duration // average over those
+	LastSeen   time.Time
+}
+
+// SuccessRate is the share of connection attempts that succeeded, 0-100.
+func (s NetworkStats) SuccessRate() int {
+	if s.Connects+s.Failures == 0 {
+		return 0
+	}
+	return s.Connects * 100 / (s.Connects + s.Failures)
+}
+
+// HistoryStats groups the history by network, most recently used first.
+func HistoryStats(entries []HistoryEntry) []NetworkStats {
+	byNetwork := map[string]*NetworkStats{}
+	total := map[string]time.Duration{}
+	for _, e := range entries {
+		if e.SSID == "" {
+			continue
+		}
+		s, ok := byNetwork[e.SSID]
+		if !ok {
+			s = &NetworkStats{SSID: e.SSID}
+			byNetwork[e.SSID] = s
+		}
+		switch e.Event {
+		case "connected":
+			s.Connects++
+		case "failed":
+			s.Failures++
+		case "disconnected":
+			if e.Duration > 0 {
+				s.Sessions++
+				total[e.SSID] += time.Duration(e.Duration) * time.Second
+			}
+		}
+		if e.Time.After(s.LastSeen) {
+			s.LastSeen = e.Time
+		}
+	}
+
+	stats := make([]NetworkStats, 0, len(byNetwork))
+	for ssid, s := range byNetwork {
+		if s.Sessions > 0 {
+			s.AvgSession = total[ssid] / time.Duration(s.Sessions)
+		}
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].LastSeen.After(stats[j].LastSeen) })
+	return stats
+}
+
+// FormatDuration renders a session length like "2h05m" or "42s".
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d <= 0:
+		return "-"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
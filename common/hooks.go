@@ -0,0 +1,36 @@
+package common
+
+// Events hook scripts can be attached to.
+var HookEvents = []string{
+	"connected", "disconnected", "activation-failed", "vpn-up", "vpn-down", "scan-completed",
+}
+
+// HookEvent describes what happened to the scripts of an event.
+type HookEvent struct {
+	Name   string // one of HookEvents
+	SSID   string
+	BSSID  string
+	Device string // interface name
+	IP     string // IPv4 address, on connected and vpn-up
+	VPN    string // VPN name, on vpn-up and vpn-down
+	Reason string // on disconnects and failures
+}
+
+// Env returns the event as NETPALA_* environment variables. Empty fields
+// are left out.
+func (e HookEvent) Env() []string {
+	env := []string{"NETPALA_EVENT=" + e.Name}
+	for _, v := range []struct{ key, value string }{
+		{"NETPALA_SSID", e.SSID},
+		{"NETPALA_BSSID", e.BSSID},
+		{"NETPALA_DEVICE", e.Device},
+		{"NETPALA_IP", e.IP},
+		{"NETPALA_VPN", e.VPN},
+		{"NETPALA_REASON", e.Reason},
+	} {
+		if v.value != "" {
+			env = append(env, v.key+"="+v.value)
+		}
+	}
+	return env
+}
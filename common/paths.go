@@ -0,0 +1,29 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// xdgDir resolves an XDG base directory with its spec default and appends
+// "netpala" to it.
+func xdgDir(env, fallback string) string {
+	dir := os.Getenv(env)
+	if dir == "" || !filepath.IsAbs(dir) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = os.TempDir()
+		}
+		dir = filepath.Join(home, fallback)
+	}
+	return filepath.Join(dir, "netpala")
+}
+
+// ConfigDir is where user-editable configuration lives ($XDG_CONFIG_HOME/netpala).
+func ConfigDir() string { return xdgDir("XDG_CONFIG_HOME", ".config") }
+
+// DataDir is where imported files such as certificates are kept ($XDG_DATA_HOME/netpala).
+func DataDir() string { return xdgDir("XDG_DATA_HOME", ".local/share") }
+
+// StateDir is where history and other runtime state is kept ($XDG_STATE_HOME/netpala).
+func StateDir() string { return xdgDir("XDG_STATE_HOME", ".local/state") }
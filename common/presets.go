@@ -0,0 +1,21 @@
+package common
+
+// BuiltinEapPresets covers roaming federations that use the same SSID
+// everywhere. The server name differs per institution, so these verify the
+// server against the system CA bundle only; a user preset with a domain match
+// is safer.
+var BuiltinEapPresets = []EapPreset{
+	{Name: "eduroam", SSID: "eduroam", Eap: "PEAP", Phase2: "MSCHAPV2", SystemCA: true},
+	{Name: "govroam", SSID: "govroam", Eap: "PEAP", Phase2: "MSCHAPV2", SystemCA: true},
+}
+
+// FindEapPreset returns the first preset for ssid. User presets come before
+// the built-in ones, so they win.
+func FindEapPreset(presets []EapPreset, ssid string) (EapPreset, bool) {
+	for _, p := range presets {
+		if p.SSID == ssid {
+			return p, true
+		}
+	}
+	return EapPreset{}, false
+}
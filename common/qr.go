@@ -0,0 +1,483 @@
+package common
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+)
+
+// A small QR code encoder (byte mode, error correction level M, versions
+// 1-10), enough for Wi-Fi credentials without pulling in a dependency.
+
+type qrVersion struct {
+	ecPerBlock           int
+	g1Blocks, g1Codeword int
+	g2Blocks, g2Codeword int
+	alignment            []int
+}
+
+var qrVersions = []qrVersion{
+	{10, 1, 16, 0, 0, nil},
+	{16, 1, 28, 0, 0, []int{6, 18}},
+	{26, 1, 44, 0, 0, []int{6, 22}},
+	{18, 2, 32, 0, 0, []int{6, 26}},
+	{24, 2, 43, 0, 0, []int{6, 30}},
+	{16, 4, 27, 0, 0, []int{6, 34}},
+	{18, 4, 31, 0, 0, []int{6, 22, 38}},
+	{22, 2, 38, 2, 39, []int{6, 24, 42}},
+	{22, 3, 36, 2, 37, []int{6, 26, 46}},
+	{26, 4, 43, 1, 44, []int{6, 28, 50}},
+}
+
+type qrCode struct {
+	size     int
+	modules  [][]bool
+	function [][]bool
+}
+
+// EncodeQR returns the module matrix (true = dark) for the given text.
+func EncodeQR(text string) ([][]bool, error) {
+	data := []byte(text)
+
+	version := 0
+	for i, v := range qrVersions {
+		countBits := 8
+		if i+1 >= 10 {
+			countBits = 16
+		}
+		capacity := (v.g1Blocks*v.g1Codeword + v.g2Blocks*v.g2Codeword) * 8
+		if 4+countBits+len(data)*8 <= capacity {
+			version = i + 1
+			break
+		}
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("text too long for a QR code (%d bytes)", len(data))
+	}
+	v := qrVersions[version-1]
+
+	// Build the data bit stream: byte mode indicator, length, payload.
+	var bits []bool
+	appendBits := func(val, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, (val>>i)&1 != 0)
+		}
+	}
+	countBits := 8
+	if version >= 10 {
+		countBits = 16
+	}
+	appendBits(0x4, 4)
+	appendBits(len(data), countBits)
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+
+	capacity := (v.g1Blocks*v.g1Codeword + v.g2Blocks*v.g2Codeword) * 8
+	appendBits(0, min(4, capacity-len(bits)))
+	for len(bits)%8 != 0 {
+		bits = append(bits, false)
+	}
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		appendBits(pad, 8)
+	}
+
+	codewords := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			codewords[i/8] |= 1 << (7 - i%8)
+		}
+	}
+
+	// Split into blocks, add error correction and interleave.
+	var blocks, ecBlocks [][]byte
+	divisor := rsDivisor(v.ecPerBlock)
+	offset := 0
+	for i := 0; i < v.g1Blocks+v.g2Blocks; i++ {
+		n := v.g1Codeword
+		if i >= v.g1Blocks {
+			n = v.g2Codeword
+		}
+		block := codewords[offset : offset+n]
+		offset += n
+		blocks = append(blocks, block)
+		ecBlocks = append(ecBlocks, rsRemainder(block, divisor))
+	}
+
+	var final []byte
+	for i := 0; i < max(v.g1Codeword, v.g2Codeword); i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				final = append(final, block[i])
+			}
+		}
+	}
+	for i := 0; i < v.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			final = append(final, block[i])
+		}
+	}
+
+	qr := newQRCode(version)
+	qr.drawCodewords(final)
+
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		qr.applyMask(mask)
+		qr.drawFormatBits(mask)
+		if penalty := qr.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		qr.applyMask(mask) // XOR again to undo
+	}
+	qr.applyMask(bestMask)
+	qr.drawFormatBits(bestMask)
+
+	return qr.modules, nil
+}
+
+func newQRCode(version int) *qrCode {
+	size := version*4 + 17
+	qr := &qrCode{size: size}
+	qr.modules = make([][]bool, size)
+	qr.function = make([][]bool, size)
+	for i := range size {
+		qr.modules[i] = make([]bool, size)
+		qr.function[i] = make([]bool, size)
+	}
+
+	// Timing patterns
+	for i := range size {
+		qr.set(6, i, i%2 == 0)
+		qr.set(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns
+	for _, c := range [][2]int{{3, 3}, {size - 4, 3}, {3, size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := c[0]+dx, c[1]+dy
+				if x >= 0 && x < size && y >= 0 && y < size {
+					dist := max(abs(dx), abs(dy))
+					qr.set(x, y, dist != 2 && dist != 4)
+				}
+			}
+		}
+	}
+
+	// Alignment patterns, skipping the ones overlapping finders
+	align := qrVersions[version-1].alignment
+	for i, ay := range align {
+		for j, ax := range align {
+			if (i == 0 && j == 0) || (i == 0 && j == len(align)-1) || (i == len(align)-1 && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					qr.set(ax+dx, ay+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve the format areas, then draw version information if needed.
+	qr.drawFormatBits(0)
+	if version >= 7 {
+		rem := version
+		for range 12 {
+			rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+		}
+		bits := version<<12 | rem
+		for i := range 18 {
+			bit := (bits>>i)&1 != 0
+			a, b := size-11+i%3, i/3
+			qr.set(a, b, bit)
+			qr.set(b, a, bit)
+		}
+	}
+	return qr
+}
+
+func (qr *qrCode) set(x, y int, dark bool) {
+	qr.modules[y][x] = dark
+	qr.function[y][x] = true
+}
+
+func (qr *qrCode) drawFormatBits(mask int) {
+	// Error correction level M has format bits 00.
+	data := mask
+	rem := data
+	for range 10 {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>i)&1 != 0 }
+
+	for i := 0; i <= 5; i++ {
+		qr.set(8, i, bit(i))
+	}
+	qr.set(8, 7, bit(6))
+	qr.set(8, 8, bit(7))
+	qr.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		qr.set(14-i, 8, bit(i))
+	}
+	for i := range 8 {
+		qr.set(qr.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		qr.set(8, qr.size-15+i, bit(i))
+	}
+	qr.set(8, qr.size-8, true)
+}
+
+func (qr *qrCode) drawCodewords(data []byte) {
+	i := 0
+	for right := qr.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := range qr.size {
+			for j := range 2 {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = qr.size - 1 - vert
+				}
+				if !qr.function[y][x] && i < len(data)*8 {
+					qr.modules[y][x] = (data[i/8]>>(7-i%8))&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (qr *qrCode) applyMask(mask int) {
+	for y := range qr.size {
+		for x := range qr.size {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !qr.function[y][x] {
+				qr.modules[y][x] = !qr.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol using the four rules from the QR specification.
+func (qr *qrCode) penalty() int {
+	size := qr.size
+	get := func(x, y int, transpose bool) bool {
+		if transpose {
+			return qr.modules[x][y]
+		}
+		return qr.modules[y][x]
+	}
+
+	score := 0
+	for _, transpose := range []bool{false, true} {
+		for y := range size {
+			run := 1
+			for x := 1; x <= size; x++ {
+				if x < size && get(x, y, transpose) == get(x-1, y, transpose) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += run - 2
+				}
+				run = 1
+			}
+
+			// Finder-like 1:1:3:1:1 patterns with four light modules on one side
+			for x := 0; x+11 <= size; x++ {
+				var pattern [11]bool
+				for k := range 11 {
+					pattern[k] = get(x+k, y, transpose)
+				}
+				core := pattern[4] && !pattern[5] && pattern[6] && pattern[7] && pattern[8] && !pattern[9] && pattern[10]
+				if !pattern[0] && !pattern[1] && !pattern[2] && !pattern[3] && core {
+					score += 40
+				}
+				rev := pattern[0] && !pattern[1] && pattern[2] && pattern[3] && pattern[4] && !pattern[5] && pattern[6]
+				if rev && !pattern[7] && !pattern[8] && !pattern[9] && !pattern[10] {
+					score += 40
+				}
+			}
+		}
+	}
+
+	dark := 0
+	for y := range size {
+		for x := range size {
+			if qr.modules[y][x] {
+				dark++
+			}
+			if x+1 < size && y+1 < size {
+				c := qr.modules[y][x]
+				if c == qr.modules[y][x+1] && c == qr.modules[y+1][x] && c == qr.modules[y+1][x+1] {
+					score += 3
+				}
+			}
+		}
+	}
+	total := size * size
+	score += abs(dark*20-total*10) / total * 10
+	return score
+}
+
+func rsMultiply(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	var root byte = 1
+	for range degree {
+		for j := range result {
+			result[j] = rsMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = rsMultiply(root, 0x02)
+	}
+	return result
+}
+
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= rsMultiply(divisor[i], factor)
+		}
+	}
+	return result
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// RenderQR draws the code with half-block characters, two modules per line,
+// surrounded by the mandatory four module quiet zone. Light modules are drawn filled so
+// the code scans on dark terminal themes.
+func RenderQR(modules [][]bool) string {
+	const quiet = 4
+	size := len(modules)
+	dark := func(x, y int) bool {
+		x, y = x-quiet, y-quiet
+		if x < 0 || y < 0 || x >= size || y >= size {
+			return false
+		}
+		return modules[y][x]
+	}
+
+	var sb strings.Builder
+	for y := 0; y < size+quiet*2; y += 2 {
+		for x := 0; x < size+quiet*2; x++ {
+			top, bottom := !dark(x, y), !dark(x, y+1)
+			if y+1 >= size+quiet*2 {
+				bottom = false
+			}
+			switch {
+			case top && bottom:
+				sb.WriteString("█")
+			case top:
+				sb.WriteString("▀")
+			case bottom:
+				sb.WriteString("▄")
+			default:
+				sb.WriteString(" ")
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// WriteQRPNG saves the code as a black-on-white PNG image.
+func WriteQRPNG(modules [][]bool, path string, scale int) error {
+	const quiet = 4
+	size := (len(modules) + quiet*2) * scale
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for y := range size {
+		for x := range size {
+			mx, my := x/scale-quiet, y/scale-quiet
+			c := color.Gray{Y: 255}
+			if mx >= 0 && my >= 0 && mx < len(modules) && my < len(modules) && modules[my][mx] {
+				c = color.Gray{Y: 0}
+			}
+			img.SetGray(x, y, c)
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return png.Encode(file, img)
+}
+
+// WifiQRString builds the WIFI: URI understood by phone cameras from a
+// profile's key-mgmt value ("" for open networks).
+func WifiQRString(ssid, keyMgmt, password string, hidden bool) (string, error) {
+	escape := strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, `:`, `\:`, `"`, `\"`)
+
+	var t string
+	switch keyMgmt {
+	case "", "owe":
+		t = "nopass"
+	case "none":
+		t = "WEP"
+	case "wpa-psk":
+		t = "WPA"
+	case "sae":
+		t = "SAE"
+	default:
+		return "", fmt.Errorf("%s networks cannot be shared with a QR code", keyMgmt)
+	}
+
+	s := fmt.Sprintf("WIFI:T:%s;S:%s;", t, escape.Replace(ssid))
+	if t != "nopass" {
+		s += fmt.Sprintf("P:%s;", escape.Replace(password))
+	}
+	if hidden {
+		s += "H:true;"
+	}
+	return s + ";", nil
+}
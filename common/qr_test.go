@@ -0,0 +1,212 @@
+package common
+
+import (
+	"strings"
+	"testing"
+)
+
+// "netpala" encoded as a version 1-M symbol, '#' = dark.
+var qrGolden = []string{
+	"#######.###...#######",
+	"#.....#..#..#.#.....#",
+	"#.###.#...###.#.###.#",
+	"#.###.#.#...#.#.###.#",
+	"#.###.#.###.#.#.###.#",
+	"#.....#.#..#..#.....#",
+	"#######.#.#.#.#######",
+	"........#####........",
+	"#...#.###.##.#####..#",
+	"##.##....#.##....###.",
+	"#.#...##.#.#..###..#.",
+	".#####.......##.#...#",
+	"#..######.#.###..#...",
+	"........###.###.####.",
+	"#######.##..##..##.#.",
+	"#.....#....##..##..##",
+	"#.###.#.#..#..####..#",
+	"#.###.#..####...#.###",
+	"#.###.#...##..#.##...",
+	"#.....#..##..##.#....",
+	"#######.###.###.....#",
+}
+
+func TestEncodeQRGolden(t *testing.T) {
+	modules, err := EncodeQR("netpala")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != len(qrGolden) {
+		t.Fatalf("got %d rows, want %d", len(modules), len(qrGolden))
+	}
+	for y, row := range modules {
+		var sb strings.Builder
+		for _, dark := range row {
+			if dark {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		if sb.String() != qrGolden[y] {
+			t.Errorf("row %d = %s, want %s", y, sb.String(), qrGolden[y])
+		}
+	}
+}
+
+// TestEncodeQRDecodes reads the version 1 symbol back the way a scanner
+// would, so the golden matrix is not just a snapshot of the encoder.
+func TestEncodeQRDecodes(t *testing.T) {
+	const text = "netpala"
+	modules, err := EncodeQR(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := len(modules)
+	dark := func(x, y int) int {
+		if modules[y][x] {
+			return 1
+		}
+		return 0
+	}
+
+	// Both copies of the format information must agree and carry level M.
+	var first, second int
+	for i := 0; i <= 5; i++ {
+		first |= dark(8, i) << i
+	}
+	first |= dark(8, 7)<<6 | dark(8, 8)<<7 | dark(7, 8)<<8
+	for i := 9; i < 15; i++ {
+		first |= dark(14-i, 8) << i
+	}
+	for i := range 8 {
+		second |= dark(size-1-i, 8) << i
+	}
+	for i := 8; i < 15; i++ {
+		second |= dark(8, size-15+i) << i
+	}
+	if first != second {
+		t.Fatalf("format copies differ: %015b != %015b", first, second)
+	}
+	format := first ^ 0x5412
+	rem := format
+	for i := 14; i >= 10; i-- {
+		if rem>>i&1 != 0 {
+			rem ^= 0x537 << (i - 10)
+		}
+	}
+	if rem != 0 {
+		t.Fatalf("format bits %015b fail the BCH check", format)
+	}
+	if level := format >> 13; level != 0 {
+		t.Fatalf("error correction level bits = %02b, want 00 (M)", level)
+	}
+	mask := format >> 10 & 7
+
+	masked := func(x, y int) bool {
+		switch mask {
+		case 0:
+			return (x+y)%2 == 0
+		case 1:
+			return y%2 == 0
+		case 2:
+			return x%3 == 0
+		case 3:
+			return (x+y)%3 == 0
+		case 4:
+			return (y/2+x/3)%2 == 0
+		case 5:
+			return x*y%2+x*y%3 == 0
+		case 6:
+			return (x*y%2+x*y%3)%2 == 0
+		default:
+			return ((x+y)%2+x*y%3)%2 == 0
+		}
+	}
+	function := func(x, y int) bool {
+		return (x < 9 && y < 9) || (x >= size-8 && y < 9) || (x < 9 && y >= size-8) || x == 6 || y == 6
+	}
+
+	var codewords []byte
+	var bit int
+	upward := true
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for i := range size {
+			y := i
+			if upward {
+				y = size - 1 - i
+			}
+			for _, x := range []int{right, right - 1} {
+				if function(x, y) {
+					continue
+				}
+				if bit%8 == 0 {
+					codewords = append(codewords, 0)
+				}
+				if modules[y][x] != masked(x, y) {
+					codewords[bit/8] |= 1 << (7 - bit%8)
+				}
+				bit++
+			}
+		}
+		upward = !upward
+	}
+	if len(codewords) != 26 {
+		t.Fatalf("read %d codewords, want 26", len(codewords))
+	}
+
+	// All ten syndromes of the Reed-Solomon codeword must be zero.
+	root := byte(1)
+	for i := range 10 {
+		var sum byte
+		for _, c := range codewords {
+			sum = rsMultiply(sum, root) ^ c
+		}
+		if sum != 0 {
+			t.Errorf("syndrome %d = %#x, want 0", i, sum)
+		}
+		root = rsMultiply(root, 2)
+	}
+
+	if mode := codewords[0] >> 4; mode != 0x4 {
+		t.Fatalf("mode = %#x, want byte mode", mode)
+	}
+	length := int(codewords[0]&0x0F)<<4 | int(codewords[1]>>4)
+	if length != len(text) {
+		t.Fatalf("length = %d, want %d", length, len(text))
+	}
+	payload := make([]byte, length)
+	for i := range payload {
+		payload[i] = codewords[1+i]<<4 | codewords[2+i]>>4
+	}
+	if string(payload) != text {
+		t.Errorf("payload = %q, want %q", payload, text)
+	}
+}
+
+func TestRenderQRQuietZone(t *testing.T) {
+	modules, err := EncodeQR("netpala")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(RenderQR(modules), "\n")
+	width := len(modules) + 8
+	for i, line := range lines {
+		if n := len([]rune(line)); n != width {
+			t.Fatalf("line %d is %d columns wide, want %d", i, n, width)
+		}
+	}
+	// Two text lines per module row: the top four rows are all light.
+	for _, line := range lines[:2] {
+		if line != strings.Repeat("█", width) {
+			t.Errorf("quiet zone line %q is not blank", line)
+		}
+	}
+	for _, line := range lines[:len(lines)-1] {
+		if !strings.HasPrefix(line, "████") || !strings.HasSuffix(line, "████") {
+			t.Errorf("line %q lacks a four module margin", line)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Access point flags as reported by NetworkManager (NM80211ApFlags).
+const (
+	APFlagPrivacy uint32 = 0x1
+)
+
+// Access point security flags (NM80211ApSecurityFlags), shared by the
+// WpaFlags and RsnFlags properties.
+const (
+	APSecPairWEP40     uint32 = 0x1
+	APSecPairWEP104    uint32 = 0x2
+	APSecPairTKIP      uint32 = 0x4
+	APSecPairCCMP      uint32 = 0x8
+	APSecGroupWEP40    uint32 = 0x10
+	APSecGroupWEP104   uint32 = 0x20
+	APSecGroupTKIP     uint32 = 0x40
+	APSecGroupCCMP     uint32 = 0x80
+	APSecKeyMgmtPSK    uint32 = 0x100
+	APSecKeyMgmt8021X  uint32 = 0x200
+	APSecKeyMgmtSAE    uint32 = 0x400
+	APSecKeyMgmtOWE    uint32 = 0x800
+	APSecKeyMgmtOWETM  uint32 = 0x1000
+	APSecKeyMgmtSuiteB uint32 = 0x2000
+)
+
+// KeyMgmt is the set of authentication schemes a network offers.
+type KeyMgmt uint8
+
+const (
+	KeyMgmtWEP KeyMgmt = 1 << iota
+	KeyMgmtPSK
+	KeyMgmtSAE
+	KeyMgmtOWE
+	KeyMgmt8021X
+	KeyMgmtSuiteB192
+)
+
+// Cipher is a set of pairwise or group ciphers.
+type Cipher uint8
+
+const (
+	CipherWEP40 Cipher = 1 << iota
+	CipherWEP104
+	CipherTKIP
+	CipherCCMP
+)
+
+// PMF is the state of 802.11w protected management frames.
+type PMF uint8
+
+const (
+	PMFUnknown PMF = iota
+	PMFDisabled
+	PMFOptional
+	PMFRequired
+)
+
+// Security describes how a network is protected. The zero value is an open
+// network.
+type Security struct {
+	KeyMgmt    KeyMgmt
+	Pairwise   Cipher
+	Group      Cipher
+	PMF        PMF
+	WPA1       bool // only advertised through the legacy WPA element
+	Transition bool // offers a newer and an older scheme side by side (WPA3/WPA2, OWE/open)
+}
+
+// SecurityFromFlags builds a descriptor from an access point's Flags,
+// WpaFlags and RsnFlags properties.
+func SecurityFromFlags(flags, wpaFlags, rsnFlags uint32) Security {
+	var s Security
+	all := wpaFlags | rsnFlags
+
+	if all&APSecKeyMgmtPSK != 0 {
+		s.KeyMgmt |= KeyMgmtPSK
+	}
+	if all&APSecKeyMgmt8021X != 0 {
+		s.KeyMgmt |= KeyMgmt8021X
+	}
+	if rsnFlags&APSecKeyMgmtSAE != 0 {
+		s.KeyMgmt |= KeyMgmtSAE
+	}
+	if rsnFlags&(APSecKeyMgmtOWE|APSecKeyMgmtOWETM) != 0 {
+		s.KeyMgmt |= KeyMgmtOWE
+	}
+	if rsnFlags&APSecKeyMgmtSuiteB != 0 {
+		s.KeyMgmt |= KeyMgmtSuiteB192
+	}
+	if s.KeyMgmt == 0 && flags&APFlagPrivacy != 0 {
+		// Privacy without WPA/RSN elements is static WEP.
+		s.KeyMgmt = KeyMgmtWEP
+	}
+
+	s.Pairwise = ciphersFromFlags(all, APSecPairWEP40, APSecPairWEP104, APSecPairTKIP, APSecPairCCMP)
+	s.Group = ciphersFromFlags(all, APSecGroupWEP40, APSecGroupWEP104, APSecGroupTKIP, APSecGroupCCMP)
+	s.WPA1 = wpaFlags != 0 && rsnFlags == 0
+
+	switch {
+	case s.KeyMgmt&KeyMgmtSAE != 0 && s.KeyMgmt&KeyMgmtPSK != 0:
+		// WPA3 transition mode: PMF is negotiated, required only for SAE clients.
+		s.Transition = true
+		s.PMF = PMFOptional
+	case rsnFlags&APSecKeyMgmtOWETM != 0:
+		s.Transition = true
+		s.PMF = PMFRequired
+	case s.KeyMgmt&(KeyMgmtSAE|KeyMgmtOWE|KeyMgmtSuiteB192) != 0:
+		// WPA3 and OWE mandate PMF.
+		s.PMF = PMFRequired
+	}
+	if wpaFlags != 0 && rsnFlags != 0 {
+		// WPA/WPA2 mixed mode
+		s.Transition = true
+	}
+	return s
+}
+
+func ciphersFromFlags(flags, wep40, wep104, tkip, ccmp uint32) Cipher {
+	var c Cipher
+	if flags&wep40 != 0 {
+		c |= CipherWEP40
+	}
+	if flags&wep104 != 0 {
+		c |= CipherWEP104
+	}
+	if flags&tkip != 0 {
+		c |= CipherTKIP
+	}
+	if flags&ccmp != 0 {
+		c |= CipherCCMP
+	}
+	return c
+}
+
+// SecurityFromKeyMgmt builds a descriptor from a saved profile's
+// 802-11-wireless-security key-mgmt value. An empty value means the profile
+// has no security setting at all.
+func SecurityFromKeyMgmt(keyMgmt string) Security {
+	switch strings.ToLower(keyMgmt) {
+	case "none":
+		return Security{KeyMgmt: KeyMgmtWEP}
+	case "ieee8021x":
+		// Dynamic WEP
+		return Security{KeyMgmt: KeyMgmtWEP | KeyMgmt8021X}
+	case "wpa-psk":
+		return Security{KeyMgmt: KeyMgmtPSK}
+	case "sae":
+		return Security{KeyMgmt: KeyMgmtSAE, PMF: PMFRequired}
+	case "owe":
+		return Security{KeyMgmt: KeyMgmtOWE, PMF: PMFRequired}
+	case "wpa-eap":
+		return Security{KeyMgmt: KeyMgmt8021X}
+	case "wpa-eap-suite-b-192":
+		return Security{KeyMgmt: KeyMgmtSuiteB192, PMF: PMFRequired}
+	}
+	return Security{}
+}
+
+// IsOpen reports whether the network has no authentication at all.
+func (s Security) IsOpen() bool {
+	return s.KeyMgmt == 0
+}
+
+// IsEnterprise reports whether the network authenticates with 802.1X.
+func (s Security) IsEnterprise() bool {
+	return s.KeyMgmt&(KeyMgmt8021X|KeyMgmtSuiteB192) != 0
+}
+
+// IsWEP reports whether the network uses static WEP keys.
+func (s Security) IsWEP() bool {
+	return s.KeyMgmt == KeyMgmtWEP
+}
+
+// NeedsPassword reports whether joining asks the user for a key.
+func (s Security) NeedsPassword() bool {
+	return !s.IsEnterprise() && s.KeyMgmt&(KeyMgmtWEP|KeyMgmtPSK|KeyMgmtSAE) != 0
+}
+
+// KeyMgmtSetting returns the key-mgmt value to create a profile with, or ""
+// for networks that need no 802-11-wireless-security setting.
+func (s Security) KeyMgmtSetting() string {
+	switch {
+	case s.KeyMgmt&KeyMgmtSuiteB192 != 0:
+		return "wpa-eap-suite-b-192"
+	case s.KeyMgmt&KeyMgmt8021X != 0 && s.KeyMgmt&KeyMgmtWEP != 0:
+		return "ieee8021x"
+	case s.KeyMgmt&KeyMgmt8021X != 0:
+		return "wpa-eap"
+	case s.KeyMgmt&KeyMgmtPSK != 0:
+		// Prefer WPA2 in transition mode; it works with every driver and the
+		// access point accepts both.
+		return "wpa-psk"
+	case s.KeyMgmt&KeyMgmtSAE != 0:
+		return "sae"
+	case s.KeyMgmt&KeyMgmtOWE != 0:
+		return "owe"
+	case s.KeyMgmt&KeyMgmtWEP != 0:
+		return "none"
+	}
+	return ""
+}
+
+// String renders the descriptor for the tables, e.g. "wpa3-sae / wpa2-psk".
+func (s Security) String() string {
+	if s.IsOpen() {
+		return "open"
+	}
+	wpa := "wpa2"
+	if s.WPA1 {
+		wpa = "wpa"
+	}
+
+	var parts []string
+	if s.KeyMgmt&KeyMgmtSAE != 0 {
+		parts = append(parts, "wpa3-sae")
+	}
+	if s.KeyMgmt&KeyMgmtSuiteB192 != 0 {
+		parts = append(parts, "wpa3-eap-192")
+	}
+	if s.KeyMgmt&KeyMgmt8021X != 0 {
+		if s.KeyMgmt&KeyMgmtWEP != 0 {
+			parts = append(parts, "wep-802.1x")
+		} else {
+			parts = append(parts, wpa+"-eap")
+		}
+	}
+	if s.KeyMgmt&KeyMgmtPSK != 0 {
+		parts = append(parts, wpa+"-psk")
+	}
+	if s.KeyMgmt&KeyMgmtOWE != 0 {
+		if s.Transition {
+			parts = append(parts, "owe / open")
+		} else {
+			parts = append(parts, "owe")
+		}
+	}
+	if s.IsWEP() {
+		parts = append(parts, "wep")
+	}
+	return strings.Join(parts, " / ")
+}
+
+// CipherString lists the pairwise ciphers, e.g. "CCMP/TKIP".
+func (s Security) CipherString() string {
+	var names []string
+	for _, c := range []struct {
+		bit  Cipher
+		name string
+	}{{CipherCCMP, "CCMP"}, {CipherTKIP, "TKIP"}, {CipherWEP104, "WEP104"}, {CipherWEP40, "WEP40"}} {
+		if s.Pairwise&c.bit != 0 {
+			names = append(names, c.name)
+		}
+	}
+	return strings.Join(names, "/")
+}
+
+// WEP key types for 802-11-wireless-security.wep-key-type.
+const (
+	WepKeyTypeKey        uint32 = 1 // 40/104-bit key as hex digits or ASCII
+	WepKeyTypePassphrase uint32 = 2 // hashed into a 104-bit key
+)
+
+// WepKeyType works out how NetworkManager should read a WEP key: 10 or 26
+// hex digits and 5 or 13 ASCII characters are raw keys, anything else is a
+// passphrase.
+func WepKeyType(key string) (uint32, error) {
+	switch len(key) {
+	case 0:
+		return 0, fmt.Errorf("a WEP key is required")
+	case 10, 26:
+		if isHex(key) {
+			return WepKeyTypeKey, nil
+		}
+	case 5, 13:
+		return WepKeyTypeKey, nil
+	}
+	if len(key) > 64 {
+		return 0, fmt.Errorf("WEP passphrases are limited to 64 characters")
+	}
+	return WepKeyTypePassphrase, nil
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+package common
+
+import (
+	"strings"
+	"time"
+)
+
+// How many samples are kept per BSSID, and how close together two samples
+// can be before the newer one replaces the older.
+const (
+	signalHistoryLen = 120
+	signalMinGap     = 2 * time.Second
+)
+
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+type SignalSample struct {
+	At     time.Time
+	Signal int
+}
+
+// SignalHistory keeps the recent signal strength samples of each BSSID for
+// the session.
+type SignalHistory map[string][]SignalSample
+
+// Record adds a sample. Refreshes often arrive in bursts, so samples taken
+// within signalMinGap of the previous one replace it.
+func (h SignalHistory) Record(bssid string, signal int, at time.Time) {
+	if bssid == "" || bssid == "-" {
+		return
+	}
+	bssid = strings.ToUpper(bssid)
+	samples := h[bssid]
+	if n := len(samples); n > 0 && at.Sub(samples[n-1].At) < signalMinGap {
+		samples[n-1] = SignalSample{At: at, Signal: signal}
+		return
+	}
+	samples = append(samples, SignalSample{At: at, Signal: signal})
+	if len(samples) > signalHistoryLen {
+		samples = samples[len(samples)-signalHistoryLen:]
+	}
+	h[bssid] = samples
+}
+
+// Samples returns the history of a BSSID, oldest first.
+func (h SignalHistory) Samples(bssid string) []SignalSample {
+	return h[strings.ToUpper(bssid)]
+}
+
+func signalLevel(signal, levels int) int {
+	level := signal * levels / 101
+	return min(max(level, 0), levels-1)
+}
+
+// Sparkline renders the last width samples as a one line chart.
+func Sparkline(samples []SignalSample, width int) string {
+	if len(samples) > width {
+		samples = samples[len(samples)-width:]
+	}
+	var b strings.Builder
+	for _, s := range samples {
+		b.WriteRune(sparkLevels[signalLevel(s.Signal, len(sparkLevels))])
+	}
+	return b.String()
+}
+
+// SignalStats returns the minimum, average and maximum of the samples.
+func SignalStats(samples []SignalSample) (lo, avg, hi int) {
+	if len(samples) == 0 {
+		return 0, 0, 0
+	}
+	lo, hi = 100, 0
+	sum := 0
+	for _, s := range samples {
+		lo, hi = min(lo, s.Signal), max(hi, s.Signal)
+		sum += s.Signal
+	}
+	return lo, sum / len(samples), hi
+}
+
+// SignalChart renders the last width samples as a chart height rows tall,
+// top row first, with eight steps per row.
+func SignalChart(samples []SignalSample, width, height int) []string {
+	if len(samples) > width {
+		samples = samples[len(samples)-width:]
+	}
+	steps := len(sparkLevels)
+	rows := make([]string, height)
+	for r := range rows {
+		var b strings.Builder
+		floor := (height - 1 - r) * steps // steps below this row
+		for _, s := range samples {
+			level := signalLevel(s.Signal, height*steps) + 1
+			switch {
+			case level >= floor+steps:
+				b.WriteRune(sparkLevels[steps-1])
+			case level > floor:
+				b.WriteRune(sparkLevels[level-floor-1])
+			default:
+				b.WriteRune(' ')
+			}
+		}
+		rows[r] = b.String()
+	}
+	return rows
+}
@@ -0,0 +1,113 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// How many rate samples the throughput graph keeps.
+const throughputHistoryLen = 60
+
+type RateSample struct {
+	At time.Time
+	Rx float64 // bytes per second
+	Tx float64
+}
+
+// Throughput follows the byte counters of one device. Totals count from the
+// first sample of the session, which restarts whenever the device connects.
+type Throughput struct {
+	Path    dbus.ObjectPath
+	startRx uint64
+	startTx uint64
+	lastRx  uint64
+	lastTx  uint64
+	lastAt  time.Time
+	Rates   []RateSample
+}
+
+// Reset starts a new session for the device at path.
+func (t *Throughput) Reset(path dbus.ObjectPath) {
+	*t = Throughput{Path: path}
+}
+
+// Record adds a reading of the device's counters.
+func (t *Throughput) Record(rx, tx uint64, at time.Time) {
+	if t.lastAt.IsZero() || rx < t.lastRx || tx < t.lastTx {
+		// First reading, or the counters were reset under us
+		t.startRx, t.startTx = rx, tx
+		t.lastRx, t.lastTx, t.lastAt = rx, tx, at
+		return
+	}
+	secs := at.Sub(t.lastAt).Seconds()
+	if secs <= 0 {
+		return
+	}
+	t.Rates = append(t.Rates, RateSample{
+		At: at,
+		Rx: float64(rx-t.lastRx) / secs,
+		Tx: float64(tx-t.lastTx) / secs,
+	})
+	if len(t.Rates) > throughputHistoryLen {
+		t.Rates = t.Rates[len(t.Rates)-throughputHistoryLen:]
+	}
+	t.lastRx, t.lastTx, t.lastAt = rx, tx, at
+}
+
+// Current returns the latest receive and transmit rates.
+func (t Throughput) Current() (rx, tx float64) {
+	if len(t.Rates) == 0 {
+		return 0, 0
+	}
+	last := t.Rates[len(t.Rates)-1]
+	return last.Rx, last.Tx
+}
+
+// Totals returns the bytes received and sent this session.
+func (t Throughput) Totals() (rx, tx uint64) {
+	return t.lastRx - t.startRx, t.lastTx - t.startTx
+}
+
+// Graph renders the combined rate of the last width samples, scaled to the
+// busiest one.
+func (t Throughput) Graph(width int) string {
+	rates := t.Rates
+	if len(rates) > width {
+		rates = rates[len(rates)-width:]
+	}
+	peak := 0.0
+	for _, r := range rates {
+		peak = max(peak, r.Rx+r.Tx)
+	}
+	var b strings.Builder
+	for _, r := range rates {
+		level := 0
+		if peak > 0 {
+			level = min(int((r.Rx+r.Tx)/peak*float64(len(sparkLevels))), len(sparkLevels)-1)
+		}
+		b.WriteRune(sparkLevels[level])
+	}
+	return b.String()
+}
+
+// FormatBytes renders a byte count with a binary unit.
+func FormatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// FormatRate renders bytes per second.
+func FormatRate(bps float64) string {
+	return FormatBytes(uint64(bps)) + "/s"
+}
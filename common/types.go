@@ -1,6 +1,8 @@
 package common
 
 import (
+	"time"
+
 	"github.com/godbus/dbus/v5"
 )
 
@@ -25,13 +27,19 @@ type DeviceUpdateMsg []Device
 type KnownNetworksUpdateMsg []KnownNetwork
 type ScannedNetworksUpdateMsg []ScannedNetwork
 type ErrMsg struct{ Err error }
+type NoticeMsg string
+type ClearNoticeMsg string
+
+// ChangeFailedMsg is a change that was refused before anything was touched.
+// It is shown like a notice, but lets a checkpoint tell it from success.
+type ChangeFailedMsg struct{ Err error }
 
 type PeriodicRefreshMsg struct{}
 type RefreshKnownNetworksMsg struct{}
 type PerformScanRefreshMsg struct{}
 type OptimisticAddMsg struct {
     SSID     string
-    Security string
+    Security Security
 }
 
 type ExitFormMsg struct{}
@@ -41,43 +49,266 @@ type SubmitEapFormMsg struct {
 type SubmitConfirmationMsg struct {
 	Value bool
 }
+type SubmitHotspotFormMsg struct {
+	Config HotspotConfig
+}
+type StopHotspotMsg struct{}
+type HotspotClientsUpdateMsg []HotspotClient
+type SecretMsg struct {
+	SSID   string
+	Label  string
+	Secret string
+}
+type SubmitSecondariesMsg struct {
+	UUIDs []string
+}
+type WireGuardLoadedMsg struct {
+	Path   dbus.ObjectPath
+	Name   string
+	Config WireGuardConfig
+}
+type SubmitWireGuardMsg struct {
+	Config WireGuardConfig
+}
+type WifiQRMsg struct {
+	SSID    string
+	Payload string
+}
+type AccessPointsMsg struct {
+	SSID string
+	APs  []AccessPoint
+}
+type ChannelScanMsg []AccessPoint
+type ConnectAccessPointMsg struct {
+	AP AccessPoint
+}
+type PinBssidMsg struct {
+	BSSID string // empty to unpin
+}
+type WirelessLoadedMsg struct {
+	Path   dbus.ObjectPath
+	Name   string
+	Config WirelessConfig
+}
+type SubmitWirelessMsg struct {
+	Config WirelessConfig
+}
+type EapPresetsMsg struct {
+	Presets []EapPreset
+	Err     error
+}
+type StatisticsEnabledMsg struct {
+	Path       dbus.ObjectPath
+	PrevRateMs uint32 // RefreshRateMs before netpala changed it
+	RxBytes    uint64
+	TxBytes    uint64
+}
+type ConnectivityUpdateMsg ConnectivityStatus
+type AirplaneModeMsg struct {
+	Enabled  bool
+	Previous RadioState // what airplane mode turned off
+}
+type ProfileDeletedMsg struct {
+	Snapshot ProfileSnapshot
+}
+type UndoFailedMsg struct {
+	Snapshot ProfileSnapshot
+	Err      error
+}
+type DeviceStateMsg struct {
+	Path      dbus.ObjectPath
+	New       uint32
+	Old       uint32
+	Reason    uint32
+	SSID      string
+	BSSID     string
+	Frequency int
+	IP        string // once activated
+}
+type VpnStateMsg struct {
+	Name      string
+	State     uint32 // NMVpnConnectionState
+	Reason    uint32
+	IP        string // once activated
+	WireGuard bool   // from a WireGuard device, so Reason is an NMDeviceStateReason
+}
+type ScanCompletedMsg struct {
+	Path dbus.ObjectPath
+}
+type HistoryMsg struct {
+	Entries []HistoryEntry
+	Err     error
+}
+type CheckpointCreatedMsg struct {
+	Path        dbus.ObjectPath
+	Description string
+	Deadline    time.Time // rolled back unless confirmed by then
+}
+type CheckpointTickMsg struct{}
+type SubmitCheckpointMsg struct {
+	Keep bool
+}
+type StatisticsMsg struct {
+	Path    dbus.ObjectPath
+	RxBytes uint64
+	TxBytes uint64
+}
 
 type Device struct {
 	Path         dbus.ObjectPath
 	Name         string
 	Mode         string
 	Powered      bool
+	SoftBlocked  bool // WirelessEnabled is off
+	HardBlocked  bool // a killswitch or firmware setting blocks the radio
 	Address      string
+	PermAddress  string // burned-in address, differs from Address while a MAC is cloned
 	State        int
 	CurrentBSSID string
 	Scanning     bool
 	Frequency    int
-	Security     string
+	Security     Security
+	Capabilities uint32
+}
+
+// ProfileSnapshot is a deleted profile, secrets included, kept so the
+// delete can be undone.
+type ProfileSnapshot struct {
+	ID       string
+	UUID     string
+	Settings map[string]map[string]dbus.Variant
+}
+
+// RadioState is the software switch of every radio airplane mode controls.
+type RadioState struct {
+	Wireless  bool
+	Wwan      bool
+	Bluetooth map[dbus.ObjectPath]bool // Powered of each BlueZ adapter
+}
+
+// AnyOn reports whether any radio is switched on.
+func (r RadioState) AnyOn() bool {
+	if r.Wireless || r.Wwan {
+		return true
+	}
+	for _, on := range r.Bluetooth {
+		if on {
+			return true
+		}
+	}
+	return false
 }
 
+// HardBlockHint explains what to do when the radio is blocked in hardware,
+// which no software switch can undo.
+const HardBlockHint = "Wi-Fi is blocked by a hardware switch or the firmware: flip the wireless switch or press the " +
+	"airplane key, and check `rfkill list` for a hard block"
+
 type KnownNetwork struct {
 	Path        dbus.ObjectPath
 	BSSID       string
 	SSID        string
-	Security    string
+	Security    Security
 	Hidden      bool
 	AutoConnect bool
 	Signal      int
 	Connected   bool
+	Secondaries []string // UUIDs of VPNs started along with this network
+	PinnedBSSID string   // 802-11-wireless.bssid, empty when any AP will do
+	VPNs        []string // names of those VPNs
 }
 
 type ScannedNetwork struct {
-	Path     dbus.ObjectPath
-	BSSID    string
-	SSID     string
-	Security string
-	Signal   int
+	Path        dbus.ObjectPath
+	BSSID       string
+	SSID        string
+	Security    Security
+	Signal      int
+	AccessPoint dbus.ObjectPath // AP to join through; empty lets NetworkManager choose
 }
 
-type VpnConnection struct {
+// AccessPoint is a single BSSID of a network.
+type AccessPoint struct {
 	Path       dbus.ObjectPath
-	ActivePath dbus.ObjectPath
-	Name       string
-	ConnType   string
-	Connected  bool
+	SSID       string
+	BSSID      string
+	Frequency  int    // MHz
+	Bandwidth  uint32 // channel width in MHz
+	MaxBitrate uint32 // kbit/s
+	Signal     int
+	LastSeen   time.Time // zero if NetworkManager never saw it in a scan
+	Security   Security
+}
+
+type VpnConnection struct {
+	Path        dbus.ObjectPath
+	ActivePath  dbus.ObjectPath
+	UUID        string
+	Name        string
+	ConnType    string
+	Connected   bool
+	AutoConnect bool
+	State       string // e.g. "connecting", "activated"; empty while inactive
+	Banner      string // login banner pushed by the VPN server
+	IP          string
+	Gateway     string
+	WifiNames   []string // known networks that bring this VPN up
+}
+
+type HotspotConfig struct {
+	SSID     string
+	Password string
+	Band     string // "bg" (2.4 GHz) or "a" (5 GHz)
+	Channel  uint32 // 0 lets NetworkManager pick
+	Security string // "wpa2-psk" or "wpa3-sae"
+}
+
+// EapPreset pre-fills the EAP form for a known enterprise network.
+type EapPreset struct {
+	Name         string
+	SSID         string
+	Eap          string // "PEAP", "TTLS", "TLS" or "PWD"
+	Phase2       string // e.g. "MSCHAPV2"
+	AnonIdentity string
+	CaCert       string
+	SystemCA     bool
+	DomainMatch  string
+	AltSubject   string
+	PeapVersion  string // "", "0" or "1"
+}
+
+// WirelessConfig holds the advanced per-network Wi-Fi settings. Zero values
+// leave the choice to NetworkManager's global defaults.
+type WirelessConfig struct {
+	Band       string // "", "a" (5 GHz) or "bg" (2.4 GHz)
+	Channel    uint32 // needs a band
+	PowerSave  uint32 // 0 default, 1 ignore, 2 disable, 3 enable
+	MTU        uint32
+	ClonedMAC  string // "", "permanent", "random", "stable", "preserve" or an address
+	WakeOnWlan uint32 // NM_SETTING_WIRELESS_WAKE_ON_WLAN flags, 1 is the default
+	PMF        int32  // 0 default, 1 disable, 2 optional, 3 required
+	Secured    bool   // whether the profile has a security setting PMF can go in
+}
+
+type HotspotClient struct {
+	MAC      string
+	IP       string
+	Hostname string
+	Expires  int64
+}
+
+type WireGuardConfig struct {
+	PrivateKey string
+	ListenPort uint32
+	FwMark     uint32
+	MTU        uint32
+	Peers      []WireGuardPeer
+}
+
+type WireGuardPeer struct {
+	PublicKey    string
+	PresharedKey string
+	Endpoint     string
+	AllowedIPs   []string
+	Keepalive    uint32
 }
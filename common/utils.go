@@ -1,7 +1,9 @@
 package common
 
 import (
+	"crypto/rand"
 	"fmt"
+	"math/big"
 	"os"
 	"strconv"
 	"strings"
@@ -18,19 +20,42 @@ func WindowDimensions() struct{ Width, Height int } {
 	return struct{ Width, Height int }{width, height}
 }
 
-func freqToBand(freq int) string {
+// FreqToBand names the band a frequency in MHz belongs to.
+func FreqToBand(freq int) string {
 	switch {
 	case freq >= 2400 && freq < 2500:
 		return "2.4 GHz"
-	case freq >= 5000 && freq < 6000:
+	case freq >= 4900 && freq < 5925:
 		return "5 GHz"
-	case freq >= 5925 && freq < 7125:
+	case freq >= 5925 && freq <= 7125:
 		return "6 GHz"
 	default:
 		return fmt.Sprintf("%d MHz", freq)
 	}
 }
 
+// FreqToChannel converts a frequency in MHz to its IEEE 802.11 channel
+// number, or 0 if it isn't a Wi-Fi channel.
+func FreqToChannel(freq int) int {
+	switch {
+	case freq == 2484:
+		return 14
+	case freq >= 2412 && freq < 2484:
+		return (freq - 2407) / 5
+	case freq == 5935:
+		// The only 6 GHz channel off the 5 MHz grid below
+		return 2
+	case freq >= 5955 && freq <= 7115:
+		return (freq - 5950) / 5
+	case freq >= 4910 && freq <= 4995:
+		// 4.9 GHz public safety band, channels 182-199
+		return (freq - 4000) / 5
+	case freq >= 5000 && freq <= 5885:
+		return (freq - 5000) / 5
+	}
+	return 0
+}
+
 func padHeaders(headers []string, headersLengths []int) []string {
 	if len(headers) == 0 {
 		return headers
@@ -137,9 +162,12 @@ func FormatDeviceData(devices []Device) [][]string {
 		padHeaders([]string{"Name", "Mode", "Powered", "Status"}, []int{-1, -1, -1, -1}), {""},
 	}
 	for _, d := range devices {
-		powered := "Off"
-		if d.Powered {
-			powered = "On"
+		powered := "On"
+		switch {
+		case d.HardBlocked:
+			powered = "Off (hardware)"
+		case d.SoftBlocked:
+			powered = "Off"
 		}
 		row := []string{d.Name, d.Mode, powered, d.Address}
 		data = append(data, row)
@@ -147,9 +175,9 @@ func FormatDeviceData(devices []Device) [][]string {
 	return data
 }
 
-func FormatStationData(devices []Device) [][]string {
+func FormatStationData(devices []Device, throughput Throughput) [][]string {
 	data := [][]string{
-		padHeaders([]string{"State", "Scanning", "Frequency", "Security"}, []int{-1, -1, -1, -1}), {""},
+		padHeaders([]string{"State", "Scanning", "Frequency", "Security", "Rate", "Session", "Traffic"}, []int{-1, -1, -1, -1, 24, 24, 22}), {""},
 	}
 	for _, d := range devices {
 		var state string
@@ -161,7 +189,19 @@ func FormatStationData(devices []Device) [][]string {
 		case 1:
 			state = "connected"
 		}
-		row := []string{state, strconv.FormatBool(d.Scanning), freqToBand(d.Frequency), d.Security}
+		security := "-"
+		if d.CurrentBSSID != "-" {
+			security = d.Security.String()
+		}
+		rate, session, graph := "-", "-", ""
+		if d.Path == throughput.Path {
+			rx, tx := throughput.Current()
+			rate = fmt.Sprintf("↓ %s ↑ %s", FormatRate(rx), FormatRate(tx))
+			totalRx, totalTx := throughput.Totals()
+			session = fmt.Sprintf("↓ %s ↑ %s", FormatBytes(totalRx), FormatBytes(totalTx))
+			graph = throughput.Graph(20)
+		}
+		row := []string{state, strconv.FormatBool(d.Scanning), FreqToBand(d.Frequency), security, rate, session, graph}
 		data = append(data, row)
 	}
 	return data
@@ -169,7 +209,7 @@ func FormatStationData(devices []Device) [][]string {
 
 func FormatVpnData(vpns []VpnConnection) [][]string {
 	data := [][]string{
-		padHeaders([]string{"", "Name", "Type"}, []int{5, -1, -1}), {""},
+		padHeaders([]string{"", "Name", "Type", "Auto Connect", "State", "Address", "Gateway", "On Wi-Fi"}, []int{5, -1, 9, 5, 12, 18, 15, -1}), {""},
 	}
 	for _, vpn := range vpns {
 		state := "     "
@@ -177,15 +217,20 @@ func FormatVpnData(vpns []VpnConnection) [][]string {
 			state = "  >  "
 		}
 
-		row := []string{state, vpn.Name, vpn.ConnType}
+		row := []string{state, vpn.Name, vpn.ConnType, strconv.FormatBool(vpn.AutoConnect), vpn.State, vpn.IP, vpn.Gateway, strings.Join(vpn.WifiNames, ", ")}
 		data = append(data, row)
 	}
 	return data
 }
 
-func FormatKnownNetworksData(networks []KnownNetwork, selectedRow int, height int) [][]string {
+// signalCell shows the current signal after a sparkline of its history.
+func signalCell(history SignalHistory, bssid string, signal int) string {
+	return fmt.Sprintf("%-10s %3d%%", Sparkline(history.Samples(bssid), 10), signal)
+}
+
+func FormatKnownNetworksData(networks []KnownNetwork, history SignalHistory, selectedRow int, height int) [][]string {
 	base := [][]string{
-		padHeaders([]string{"", "Name", "Security", "Hidden", "Auto Connect", "Signal"}, []int{5, -1, 23, 5, 5, 6}), {""},
+		padHeaders([]string{"", "Name", "Security", "Hidden", "Auto Connect", "Signal", "VPN"}, []int{5, -1, 23, 5, 5, 15, -1}), {""},
 	}
 	window := FormatArrays(networks, selectedRow, height)
 	for _, n := range window {
@@ -193,7 +238,7 @@ func FormatKnownNetworksData(networks []KnownNetwork, selectedRow int, height in
 		if n.Connected {
 			connected = "  >  "
 		}
-		row := []string{connected, n.SSID, n.Security, strconv.FormatBool(n.Hidden), strconv.FormatBool(n.AutoConnect), strconv.Itoa(n.Signal) + "%"}
+		row := []string{connected, n.SSID, n.Security.String(), strconv.FormatBool(n.Hidden), strconv.FormatBool(n.AutoConnect), signalCell(history, n.BSSID, n.Signal), strings.Join(n.VPNs, ", ")}
 		base = append(base, row)
 	}
 
@@ -206,13 +251,13 @@ func FormatKnownNetworksData(networks []KnownNetwork, selectedRow int, height in
 	return base
 }
 
-func FormatScannedNetworksData(networks []ScannedNetwork, selectedRow int, height int) [][]string {
+func FormatScannedNetworksData(networks []ScannedNetwork, history SignalHistory, selectedRow int, height int) [][]string {
 	data := [][]string{
 		padHeaders([]string{"Name", "Security", "Signal"}, []int{-1, -1, -1}), {""},
 	}
 	window := FormatArrays(networks, selectedRow, height)
 	for _, n := range window {
-		row := []string{n.SSID, n.Security, strconv.Itoa(n.Signal) + "%"}
+		row := []string{n.SSID, n.Security.String(), signalCell(history, n.BSSID, n.Signal)}
 		data = append(data, row)
 	}
 	for i := 0; i < height-len(networks); i++ {
@@ -247,3 +292,19 @@ func CalculatePadding(s string) int {
 	// Calculate padding and ensure it's not negative
 	return max(0, (totalWidth-textWidth)/2)
 }
+
+// GeneratePassword returns a random alphanumeric passphrase of the given length.
+// Ambiguous characters (0/O, 1/l/I) are left out so it can be read aloud.
+func GeneratePassword(length int) string {
+	const charset = "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	buf := make([]byte, length)
+	limit := big.NewInt(int64(len(charset)))
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return ""
+		}
+		buf[i] = charset[n.Int64()]
+	}
+	return string(buf)
+}
@@ -0,0 +1,85 @@
+package common
+
+import "fmt"
+
+// NMVpnConnectionState
+var vpnStates = map[uint32]string{
+	0: "unknown",
+	1: "preparing",
+	2: "needs auth",
+	3: "connecting",
+	4: "getting IP",
+	5: "activated",
+	6: "failed",
+	7: "disconnected",
+}
+
+// NMActiveConnectionState, used by WireGuard and other non-plugin VPNs
+var activeStates = map[uint32]string{
+	0: "unknown",
+	1: "activating",
+	2: "activated",
+	3: "deactivating",
+	4: "deactivated",
+}
+
+// NMActiveConnectionStateReason, as sent with VpnStateChanged
+var vpnFailureReasons = map[uint32]string{
+	0:  "unknown reason",
+	1:  "no reason given",
+	2:  "disconnected by user",
+	3:  "the underlying network connection was lost",
+	4:  "the VPN service stopped unexpectedly",
+	5:  "the VPN service returned an invalid IP configuration",
+	6:  "the connection attempt timed out",
+	7:  "the VPN service did not start in time",
+	8:  "the VPN service failed to start",
+	9:  "no valid secrets were provided",
+	10: "login failed (check the username and password)",
+	11: "the connection was deleted",
+	12: "a connection it depends on failed",
+	13: "the VPN device could not be created",
+	14: "the VPN device disappeared",
+}
+
+const (
+	VpnStateActivated    uint32 = 5
+	VpnStateFailed       uint32 = 6
+	VpnStateDisconnected uint32 = 7
+)
+
+// VpnStateString names a VPN plugin connection state.
+func VpnStateString(state uint32) string {
+	if s, ok := vpnStates[state]; ok {
+		return s
+	}
+	return fmt.Sprintf("state %d", state)
+}
+
+// ActiveStateString names an active connection state.
+func ActiveStateString(state uint32) string {
+	if s, ok := activeStates[state]; ok {
+		return s
+	}
+	return fmt.Sprintf("state %d", state)
+}
+
+// VpnFailureReason explains why a VPN connection went down.
+func VpnFailureReason(reason uint32) string {
+	if s, ok := vpnFailureReasons[reason]; ok {
+		return s
+	}
+	return fmt.Sprintf("reason %d", reason)
+}
+
+// IsVpnFailure reports whether a VpnStateChanged signal means the
+// connection went down for a reason the user should hear about.
+func IsVpnFailure(state, reason uint32) bool {
+	switch state {
+	case VpnStateFailed:
+		return true
+	case VpnStateDisconnected:
+		return reason > 2 && reason != 11
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package common
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// GenerateWireGuardKey returns a new base64 encoded Curve25519 private key,
+// clamped the same way `wg genkey` does.
+func GenerateWireGuardKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	key[0] &= 248
+	key[31] = (key[31] & 127) | 64
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// WireGuardPublicKey derives the public key to share with peers.
+func WireGuardPublicKey(privateKey string) (string, error) {
+	raw, err := decodeWireGuardKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+	priv, err := ecdh.X25519().NewPrivateKey(raw)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(priv.PublicKey().Bytes()), nil
+}
+
+// ValidateWireGuardKey checks that a key is 32 bytes of base64.
+func ValidateWireGuardKey(key string) error {
+	_, err := decodeWireGuardKey(key)
+	return err
+}
+
+func decodeWireGuardKey(key string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(raw) != 32 {
+		return nil, fmt.Errorf("'%s' is not a valid WireGuard key", key)
+	}
+	return raw, nil
+}
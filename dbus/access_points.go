@@ -0,0 +1,55 @@
+package dbus
+
+import (
+	"fmt"
+	"net"
+
+	"netpala/common"
+	"netpala/network"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/godbus/dbus/v5"
+)
+
+// LoadAccessPointsCmd lists the access points of a network for the AP view.
+func LoadAccessPointsCmd(conn *dbus.Conn, ssid string) tea.Cmd {
+	return func() tea.Msg {
+		return common.AccessPointsMsg{SSID: ssid, APs: network.GetAccessPoints(conn, ssid)}
+	}
+}
+
+// LoadChannelsCmd collects every access point in range for the channel
+// analyzer.
+func LoadChannelsCmd(conn *dbus.Conn) tea.Cmd {
+	return func() tea.Msg {
+		return common.ChannelScanMsg(network.GetAllAccessPoints(conn))
+	}
+}
+
+// PinBssidCmd locks a saved profile to one access point, or lets it roam
+// again when bssid is empty.
+func PinBssidCmd(conn *dbus.Conn, connectionPath dbus.ObjectPath, bssid string) tea.Cmd {
+	return func() tea.Msg {
+		var mac net.HardwareAddr
+		if bssid != "" {
+			var err error
+			if mac, err = net.ParseMAC(bssid); err != nil {
+				return common.ErrMsg{Err: fmt.Errorf("invalid bssid '%s': %w", bssid, err)}
+			}
+		}
+		err := updateConnection(conn, connectionPath, func(settings map[string]map[string]dbus.Variant) {
+			if mac == nil {
+				delete(settings["802-11-wireless"], "bssid")
+			} else {
+				settings["802-11-wireless"]["bssid"] = dbus.MakeVariant([]byte(mac))
+			}
+		})
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		if mac == nil {
+			return common.NoticeMsg("Unpinned, any access point will be used")
+		}
+		return common.NoticeMsg(fmt.Sprintf("Pinned to %s", bssid))
+	}
+}
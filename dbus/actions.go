@@ -15,6 +15,12 @@ import (
 
 // connectToNetworkCmd tells NetworkManager to activate a connection on a specific device.
 func ConnectToNetworkCmd(conn *dbus.Conn, connectionPath, devicePath dbus.ObjectPath) tea.Cmd {
+	return ConnectToAccessPointCmd(conn, connectionPath, devicePath, "/")
+}
+
+// ConnectToAccessPointCmd activates a saved connection through a specific
+// access point ("/" lets NetworkManager pick the best one).
+func ConnectToAccessPointCmd(conn *dbus.Conn, connectionPath, devicePath, apPath dbus.ObjectPath) tea.Cmd {
 	return func() tea.Msg {
 		nm := conn.Object(network.NMDest, dbus.ObjectPath(network.NMPath))
 
@@ -24,7 +30,7 @@ func ConnectToNetworkCmd(conn *dbus.Conn, connectionPath, devicePath dbus.Object
 			0,
 			connectionPath,
 			devicePath,
-			dbus.ObjectPath("/"),
+			apPath,
 		)
 
 		if call.Err != nil {
@@ -60,23 +66,33 @@ func AddAndConnectToNetworkCmd(conn *dbus.Conn, net common.ScannedNetwork, passw
 			"ipv4": {"method": dbus.MakeVariant("auto")},
 			"ipv6": {"method": dbus.MakeVariant("auto")},
 		}
-		securitySettings := make(map[string]dbus.Variant)
-		switch net.Security {
-		case "wpa3-sae":
-			securitySettings["key-mgmt"] = dbus.MakeVariant("sae")
-			securitySettings["psk"] = dbus.MakeVariant(password)
-		case "wpa2-psk":
-			securitySettings["key-mgmt"] = dbus.MakeVariant("wpa-psk")
-			securitySettings["psk"] = dbus.MakeVariant(password)
+		switch keyMgmt := net.Security.KeyMgmtSetting(); keyMgmt {
+		case "":
+			// Open network, no security setting at all
+			delete(settings["802-11-wireless"], "security")
+		case "none":
+			// Static WEP
+			keyType, err := common.WepKeyType(password)
+			if err != nil {
+				return common.NoticeMsg(err.Error())
+			}
+			settings["802-11-wireless-security"] = map[string]dbus.Variant{
+				"key-mgmt":      dbus.MakeVariant(keyMgmt),
+				"auth-alg":      dbus.MakeVariant("open"),
+				"wep-tx-keyidx": dbus.MakeVariant(uint32(0)),
+				"wep-key0":      dbus.MakeVariant(password),
+				"wep-key-type":  dbus.MakeVariant(keyType),
+			}
+		case "owe":
+			settings["802-11-wireless-security"] = map[string]dbus.Variant{
+				"key-mgmt": dbus.MakeVariant(keyMgmt),
+			}
 		default:
-			if net.Security != "open" {
-				securitySettings["key-mgmt"] = dbus.MakeVariant("wpa-psk")
-				securitySettings["psk"] = dbus.MakeVariant(password)
+			settings["802-11-wireless-security"] = map[string]dbus.Variant{
+				"key-mgmt": dbus.MakeVariant(keyMgmt),
+				"psk":      dbus.MakeVariant(password),
 			}
 		}
-		if len(securitySettings) > 0 {
-			settings["802-11-wireless-security"] = securitySettings
-		}
 
 		// 3. Add the connection via D-Bus
 		settingsObj := conn.Object(network.NMDest, "/org/freedesktop/NetworkManager/Settings")
@@ -97,7 +113,7 @@ func AddAndConnectToNetworkCmd(conn *dbus.Conn, net common.ScannedNetwork, passw
 		// 6. Create the optimistic update message
 		optimisticMsg := common.OptimisticAddMsg{
 			SSID:     net.SSID,
-			Security: net.Security, // Use the security string from scanned network
+			Security: net.Security, // Use the security of the scanned network
 		}
 
 		// 7. Batch commands based on success
@@ -107,7 +123,11 @@ func AddAndConnectToNetworkCmd(conn *dbus.Conn, net common.ScannedNetwork, passw
 
 		if err == nil {
 			// If we got the path, attempt connection
-			batchCmds = append(batchCmds, ConnectToNetworkCmd(conn, newConnectionPath, devicePath))
+			apPath := net.AccessPoint
+			if apPath == "" {
+				apPath = "/"
+			}
+			batchCmds = append(batchCmds, ConnectToAccessPointCmd(conn, newConnectionPath, devicePath, apPath))
 		} else {
 			// If we didn't get the path, report the error but still refresh
 			batchCmds = append(batchCmds, func() tea.Msg { return common.ErrMsg{Err: fmt.Errorf("added connection but failed to read path: %w", err)} })
@@ -116,8 +136,10 @@ func AddAndConnectToNetworkCmd(conn *dbus.Conn, net common.ScannedNetwork, passw
 	}
 }
 
-// AddAndConnectEAPCmd adds a WPA-EAP network and attempts connection.
-func AddAndConnectEAPCmd(conn *dbus.Conn, config map[string]string, devicePath dbus.ObjectPath) tea.Cmd {
+// AddAndConnectEAPCmd adds an 802.1X network (WPA-EAP, WPA3-Enterprise
+// 192-bit or dynamic WEP, as its scanned security says) and attempts
+// connection.
+func AddAndConnectEAPCmd(conn *dbus.Conn, config map[string]string, security common.Security, devicePath dbus.ObjectPath) tea.Cmd {
 	return func() tea.Msg {
 		// 1. Validate required fields
 		ssid, ok := config["ssid"]
@@ -143,16 +165,73 @@ func AddAndConnectEAPCmd(conn *dbus.Conn, config map[string]string, devicePath d
 		eapSettings := map[string]dbus.Variant{
 			"eap":      dbus.MakeVariant([]string{strings.ToLower(eapMethod)}),
 			"identity": dbus.MakeVariant(identity),
-			"password": dbus.MakeVariant(config["password"]),
 		}
-		if phase2, ok := config["phase2-auth"]; ok && phase2 != "" && phase2 != "NONE" {
+		if strings.EqualFold(eapMethod, "TLS") {
+			// TLS authenticates with the client certificate instead of a password
+			certPath := config["client_cert"]
+			if certPath == "" {
+				return common.ErrMsg{Err: fmt.Errorf("EAP-TLS config is missing a client certificate")}
+			}
+			keyPath := config["private_key"]
+			if keyPath == "" && common.IsPKCS12(certPath) {
+				// A PKCS#12 bundle is passed as both certificate and key
+				keyPath = certPath
+			}
+			if keyPath == "" {
+				return common.ErrMsg{Err: fmt.Errorf("EAP-TLS config is missing a private key")}
+			}
+			eapSettings["client-cert"] = dbus.MakeVariant(network.CertValue(certPath))
+			eapSettings["private-key"] = dbus.MakeVariant(network.CertValue(keyPath))
+			eapSettings["private-key-password"] = dbus.MakeVariant(config["private_key_password"])
+			eapSettings["private-key-password-flags"] = dbus.MakeVariant(uint32(0))
+		} else {
+			eapSettings["password"] = dbus.MakeVariant(config["password"])
+		}
+		if phase2, ok := config["phase2-auth"]; ok && phase2 != "" && phase2 != "NONE" && !strings.EqualFold(eapMethod, "TLS") {
 			eapSettings["phase2-auth"] = dbus.MakeVariant(strings.ToLower(phase2))
 		}
 		if certPath, ok := config["ca_cert"]; ok && certPath != "" {
-			eapSettings["ca-cert"] = dbus.MakeVariant("file://" + certPath)
+			eapSettings["ca-cert"] = dbus.MakeVariant(network.CertValue(certPath))
+		}
+		if config["system_ca_certs"] == "true" {
+			eapSettings["system-ca-certs"] = dbus.MakeVariant(true)
+		}
+		if anon := config["anonymous_identity"]; anon != "" {
+			eapSettings["anonymous-identity"] = dbus.MakeVariant(anon)
+		}
+		if domain := config["domain_suffix_match"]; domain != "" {
+			eapSettings["domain-suffix-match"] = dbus.MakeVariant(domain)
+		}
+		if alt := config["altsubject_matches"]; alt != "" {
+			var matches []string
+			for _, match := range strings.Split(alt, ",") {
+				if match = strings.TrimSpace(match); match != "" {
+					matches = append(matches, match)
+				}
+			}
+			eapSettings["altsubject-matches"] = dbus.MakeVariant(matches)
+		}
+		if version := config["peap_version"]; version != "" {
+			eapSettings["phase1-peapver"] = dbus.MakeVariant(version)
+		}
+		if config["peap_label"] == "1" {
+			eapSettings["phase1-peaplabel"] = dbus.MakeVariant("1")
 		}
 
 		// 4. Build complete settings map
+		keyMgmt := security.KeyMgmtSetting()
+		if !security.IsEnterprise() {
+			// Not from a scan (e.g. a hidden network), assume plain WPA-EAP
+			keyMgmt = "wpa-eap"
+			security = common.Security{KeyMgmt: common.KeyMgmt8021X}
+		}
+		wirelessSecurity := map[string]dbus.Variant{
+			"key-mgmt": dbus.MakeVariant(keyMgmt),
+		}
+		if keyMgmt == "wpa-eap-suite-b-192" {
+			// WPA3-Enterprise 192-bit mandates PMF
+			wirelessSecurity["pmf"] = dbus.MakeVariant(int32(3))
+		}
 		settings := map[string]map[string]dbus.Variant{
 			"connection": {
 				"id":          dbus.MakeVariant(ssid),
@@ -165,12 +244,10 @@ func AddAndConnectEAPCmd(conn *dbus.Conn, config map[string]string, devicePath d
 				"mode":     dbus.MakeVariant("infrastructure"),
 				"security": dbus.MakeVariant("802-11-wireless-security"),
 			},
-			"802-11-wireless-security": {
-				"key-mgmt": dbus.MakeVariant("wpa-eap"),
-			},
-			"802-1x": eapSettings,
-			"ipv4":   {"method": dbus.MakeVariant("auto")},
-			"ipv6":   {"method": dbus.MakeVariant("auto")},
+			"802-11-wireless-security": wirelessSecurity,
+			"802-1x":                   eapSettings,
+			"ipv4":                     {"method": dbus.MakeVariant("auto")},
+			"ipv6":                     {"method": dbus.MakeVariant("auto")},
 		}
 
 		// 5. Add the connection via D-Bus
@@ -192,7 +269,7 @@ func AddAndConnectEAPCmd(conn *dbus.Conn, config map[string]string, devicePath d
 		// 8. Create the optimistic update message
 		optimisticMsg := common.OptimisticAddMsg{
 			SSID:     ssid,
-			Security: "wpa2-eap", // Generally correct assumption for EAP
+			Security: security,
 		}
 
 		// 9. Batch commands based on success
@@ -265,9 +342,19 @@ func ToggleWifiCmd(conn *dbus.Conn, enable bool) tea.Cmd {
 	}
 }
 
-// DeleteConnectionCmd tells NetworkManager to delete a saved connection profile.
+// DeleteConnectionCmd tells NetworkManager to delete a saved connection
+// profile. The profile is read first, secrets included, so the delete can be
+// undone.
 func DeleteConnectionCmd(conn *dbus.Conn, connectionPath dbus.ObjectPath) tea.Cmd {
 	return func() tea.Msg {
+		settings, err := network.GetSettingsWithSecrets(conn, connectionPath)
+		if err != nil {
+			return common.ErrMsg{Err: fmt.Errorf("failed to save connection %s before deleting it: %w", connectionPath, err)}
+		}
+		snapshot := common.ProfileSnapshot{Settings: settings}
+		snapshot.ID, _ = settings["connection"]["id"].Value().(string)
+		snapshot.UUID, _ = settings["connection"]["uuid"].Value().(string)
+
 		connObj := conn.Object(network.NMDest, connectionPath)
 		call := connObj.Call(
 			"org.freedesktop.NetworkManager.Settings.Connection.Delete",
@@ -276,7 +363,14 @@ func DeleteConnectionCmd(conn *dbus.Conn, connectionPath dbus.ObjectPath) tea.Cm
 		if call.Err != nil {
 			return common.ErrMsg{Err: fmt.Errorf("failed to delete connection %s: %w", connectionPath, call.Err)}
 		}
-		// Success handled by signal listener
-		return nil
+		// The tables are refreshed by the signal listener
+		return common.ProfileDeletedMsg{Snapshot: snapshot}
 	}
-}
\ No newline at end of file
+}
+
+// ClearNoticeCmd hides a status bar notice after a few seconds.
+func ClearNoticeCmd(notice common.NoticeMsg) tea.Cmd {
+	return tea.Tick(5*time.Second, func(t time.Time) tea.Msg {
+		return common.ClearNoticeMsg(notice)
+	})
+}
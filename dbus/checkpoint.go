@@ -0,0 +1,108 @@
+package dbus
+
+import (
+	"fmt"
+	"time"
+
+	"netpala/common"
+	"netpala/network"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/godbus/dbus/v5"
+)
+
+// How long the user has to confirm a change. NetworkManager is given a
+// little longer, so it only rolls back on its own when netpala is gone.
+const (
+	CheckpointTimeout   = 30 * time.Second
+	checkpointGraceSecs = 5
+)
+
+// WithCheckpointCmd runs cmd inside a NetworkManager checkpoint so the change
+// can be rolled back if it cuts the connection. If no checkpoint can be
+// created the change is still made, with a notice saying so.
+func WithCheckpointCmd(conn *dbus.Conn, description string, cmd tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		path, err := network.CreateCheckpoint(conn, uint32(CheckpointTimeout/time.Second)+checkpointGraceSecs)
+		if err != nil {
+			notice := common.NoticeMsg(fmt.Sprintf("No checkpoint, so %s can't be rolled back: %v", description, err))
+			return batchMsgs(cmd(), notice)
+		}
+		results, failed := flattenMsg(cmd())
+		if failed {
+			// Nothing to confirm when the change didn't go through
+			network.DestroyCheckpoint(conn, path)
+			return batchMsgs(results...)
+		}
+		created := common.CheckpointCreatedMsg{Path: path, Description: description, Deadline: time.Now().Add(CheckpointTimeout)}
+		return batchMsgs(append(results, created)...)
+	}
+}
+
+// flattenMsg runs the commands of a batch so their messages can be checked
+// for failures, and reports whether any of them failed. The wrapped commands
+// must finish on their own, so listeners can't be part of a checkpoint.
+func flattenMsg(msg tea.Msg) ([]tea.Msg, bool) {
+	switch msg := msg.(type) {
+	case nil:
+		return nil, false
+	case common.ErrMsg, common.ChangeFailedMsg:
+		return []tea.Msg{msg}, true
+	case tea.BatchMsg:
+		var msgs []tea.Msg
+		failed := false
+		for _, cmd := range msg {
+			if cmd == nil {
+				continue
+			}
+			sub, subFailed := flattenMsg(cmd())
+			msgs = append(msgs, sub...)
+			failed = failed || subFailed
+		}
+		return msgs, failed
+	}
+	return []tea.Msg{msg}, false
+}
+
+// CheckpointTickCmd drives the countdown of the confirmation popup.
+func CheckpointTickCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return common.CheckpointTickMsg{}
+	})
+}
+
+// FinishCheckpointCmd keeps or rolls back the changes made since a
+// checkpoint.
+func FinishCheckpointCmd(conn *dbus.Conn, path dbus.ObjectPath, description string, keep bool) tea.Cmd {
+	return func() tea.Msg {
+		if keep {
+			if err := network.DestroyCheckpoint(conn, path); err != nil {
+				// It's gone when NetworkManager already rolled back
+				return common.NoticeMsg(fmt.Sprintf("Checkpoint already gone, %s may have been rolled back: %v", description, err))
+			}
+			return common.NoticeMsg(fmt.Sprintf("Kept: %s", description))
+		}
+		failed, err := network.RollbackCheckpoint(conn, path)
+		if err != nil {
+			return common.NoticeMsg(err.Error())
+		}
+		if len(failed) > 0 {
+			return common.NoticeMsg(fmt.Sprintf("Rolled back %s, but %d device(s) couldn't be restored", description, len(failed)))
+		}
+		return common.NoticeMsg(fmt.Sprintf("Rolled back: %s", description))
+	}
+}
+
+// batchMsgs returns the non-nil messages as one.
+func batchMsgs(msgs ...tea.Msg) tea.Msg {
+	var batch tea.BatchMsg
+	for _, msg := range msgs {
+		if msg != nil {
+			batch = append(batch, func() tea.Msg { return msg })
+		}
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+	return batch
+}
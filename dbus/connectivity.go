@@ -0,0 +1,64 @@
+package dbus
+
+import (
+	"fmt"
+	"os/exec"
+
+	"netpala/common"
+	"netpala/network"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/godbus/dbus/v5"
+)
+
+// LoadConnectivityCmd reads the current connectivity state.
+func LoadConnectivityCmd(conn *dbus.Conn) tea.Cmd {
+	return func() tea.Msg {
+		return common.ConnectivityUpdateMsg(network.GetConnectivity(conn))
+	}
+}
+
+// CheckConnectivityCmd runs a connectivity check and reports the result.
+func CheckConnectivityCmd(conn *dbus.Conn) tea.Cmd {
+	return func() tea.Msg {
+		if err := network.CheckConnectivity(conn); err != nil {
+			return common.NoticeMsg(err.Error())
+		}
+		status := network.GetConnectivity(conn)
+		return tea.BatchMsg{
+			func() tea.Msg { return common.ConnectivityUpdateMsg(status) },
+			func() tea.Msg {
+				return common.NoticeMsg("Connectivity: " + common.ConnectivityString(status.Connectivity))
+			},
+		}
+	}
+}
+
+// SetConnectivityCheckCmd enables or disables periodic connectivity checks.
+func SetConnectivityCheckCmd(conn *dbus.Conn, enabled bool) tea.Cmd {
+	return func() tea.Msg {
+		if err := network.SetConnectivityCheck(conn, enabled); err != nil {
+			return common.NoticeMsg(err.Error())
+		}
+		state := "off"
+		if enabled {
+			state = "on"
+		}
+		return tea.BatchMsg{
+			func() tea.Msg { return common.ConnectivityUpdateMsg(network.GetConnectivity(conn)) },
+			func() tea.Msg { return common.NoticeMsg("Connectivity checks " + state) },
+		}
+	}
+}
+
+// OpenURLCmd opens a URL in the desktop's default browser.
+func OpenURLCmd(url string) tea.Cmd {
+	return func() tea.Msg {
+		cmd := exec.Command("xdg-open", url)
+		if err := cmd.Start(); err != nil {
+			return common.NoticeMsg(fmt.Sprintf("Couldn't open %s: %v", url, err))
+		}
+		go cmd.Wait()
+		return common.NoticeMsg("Opened " + url + " in your browser")
+	}
+}
@@ -1,6 +1,7 @@
 package dbus
 
 import (
+	"fmt"
 	"netpala/common"
 	"netpala/network"
 	"time"
@@ -20,15 +21,34 @@ func WaitForDBusSignal(conn *dbus.Conn, sig chan *dbus.Signal) tea.Cmd {
 			// Body[0] is the interface name whose properties changed.
 			if len(s.Body) > 0 {
 				if iface, ok := s.Body[0].(string); ok {
+					if iface == network.DevStatsIF {
+						if msg, ok := statisticsMsg(conn, s); ok {
+							return msg
+						}
+					}
 					// --- THIS IS THE FIX ---
 					// Check if properties changed on the main NM object OR a Device object.
 					// This ensures we catch WirelessEnabled changes AND device state/scanning changes.
 					if iface == network.NMDest || iface == network.DevIF || iface == network.WifiIF {
 						// Refresh devices and potentially VPNs (as device state affects VPN)
-						return tea.BatchMsg{
+						msgs := tea.BatchMsg{
 							func() tea.Msg { return common.DeviceUpdateMsg(network.GetDevicesData(conn)) },
 							func() tea.Msg { return common.VpnUpdateMsg(network.GetVpnData(conn)) }, // VPN status might depend on device state
 						}
+						if iface == network.NMDest {
+							// State and Connectivity live on the main object
+							msgs = append(msgs, func() tea.Msg { return common.ConnectivityUpdateMsg(network.GetConnectivity(conn)) })
+						}
+						if iface == network.WifiIF && len(s.Body) > 1 {
+							// LastScan changes when a scan finishes
+							if changed, ok := s.Body[1].(map[string]dbus.Variant); ok {
+								if _, ok := changed["LastScan"]; ok {
+									scan := common.ScanCompletedMsg{Path: s.Path}
+									msgs = append(msgs, func() tea.Msg { return scan })
+								}
+							}
+						}
+						return msgs
 					}
 					// --- END FIX ---
 				}
@@ -39,20 +59,53 @@ func WaitForDBusSignal(conn *dbus.Conn, sig chan *dbus.Signal) tea.Cmd {
 			"org.freedesktop.NetworkManager.DeviceRemoved",
 			"org.freedesktop.NetworkManager.Device.StateChanged":
 			// Device state changes definitely affect connectivity. Refresh relevant lists.
-			return tea.BatchMsg{
+			msgs := tea.BatchMsg{
 				func() tea.Msg { return common.DeviceUpdateMsg(network.GetDevicesData(conn)) },
 				func() tea.Msg { return common.KnownNetworksUpdateMsg(network.GetKnownNetworks(conn)) },
 				func() tea.Msg { return common.VpnUpdateMsg(network.GetVpnData(conn)) }, // VPN status might depend on device state
 			}
+			if msg, ok := deviceStateMsg(conn, s); ok {
+				msgs = append(msgs, func() tea.Msg { return msg })
+			}
+			if msg, ok := wireGuardStateMsg(conn, s); ok {
+				msgs = append(msgs, func() tea.Msg { return msg })
+			}
+			return msgs
 
 		case "org.freedesktop.NetworkManager.Settings.NewConnection",
-			"org.freedesktop.NetworkManager.Settings.ConnectionRemoved":
-			// Adding/Removing connections affects Known Networks and VPN lists.
+			"org.freedesktop.NetworkManager.Settings.ConnectionRemoved",
+			"org.freedesktop.NetworkManager.Settings.Connection.Updated":
+			// Adding/Removing/Editing connections affects Known Networks and VPN lists.
 			return tea.BatchMsg{
 				func() tea.Msg { return common.KnownNetworksUpdateMsg(network.GetKnownNetworks(conn)) },
 				func() tea.Msg { return common.VpnUpdateMsg(network.GetVpnData(conn)) },
 			}
 
+		case network.VpnIF + ".VpnStateChanged":
+			// Body is (state, reason). Refresh the VPN table and explain failures.
+			msgs := tea.BatchMsg{
+				func() tea.Msg { return common.DeviceUpdateMsg(network.GetDevicesData(conn)) },
+				func() tea.Msg { return common.VpnUpdateMsg(network.GetVpnData(conn)) },
+			}
+			if len(s.Body) >= 2 {
+				state, _ := s.Body[0].(uint32)
+				reason, _ := s.Body[1].(uint32)
+				name := "VPN"
+				if id, err := conn.Object(network.NMDest, s.Path).GetProperty("org.freedesktop.NetworkManager.Connection.Active.Id"); err == nil {
+					name, _ = id.Value().(string)
+				}
+				vpnState := common.VpnStateMsg{Name: name, State: state, Reason: reason}
+				if state == common.VpnStateActivated {
+					vpnState.IP = network.GetIPv4Address(conn, s.Path, "org.freedesktop.NetworkManager.Connection.Active")
+				}
+				msgs = append(msgs, func() tea.Msg { return vpnState })
+				if common.IsVpnFailure(state, reason) {
+					notice := common.NoticeMsg(fmt.Sprintf("%s disconnected: %s", name, common.VpnFailureReason(reason)))
+					msgs = append(msgs, func() tea.Msg { return notice })
+				}
+			}
+			return msgs
+
 		case "org.freedesktop.NetworkManager.Device.Wireless.AccessPointAdded",
 			"org.freedesktop.NetworkManager.Device.Wireless.AccessPointRemoved":
 			// Signals that scan results *might* have changed. Trigger debounce.
@@ -64,6 +117,90 @@ func WaitForDBusSignal(conn *dbus.Conn, sig chan *dbus.Signal) tea.Cmd {
 	}
 }
 
+// statisticsMsg reads the byte counters out of a Device.Statistics
+// PropertiesChanged signal. Counters missing from it haven't changed, but
+// are read back so the message is complete.
+func statisticsMsg(conn *dbus.Conn, s *dbus.Signal) (tea.Msg, bool) {
+	if len(s.Body) < 2 {
+		return nil, false
+	}
+	changed, _ := s.Body[1].(map[string]dbus.Variant)
+	rx, rxOk := changed["RxBytes"].Value().(uint64)
+	tx, txOk := changed["TxBytes"].Value().(uint64)
+	if !rxOk && !txOk {
+		// Only RefreshRateMs changed
+		return nil, false
+	}
+	if !rxOk || !txOk {
+		curRx, curTx := network.GetStatistics(conn, s.Path)
+		if !rxOk {
+			rx = curRx
+		}
+		if !txOk {
+			tx = curTx
+		}
+	}
+	return common.StatisticsMsg{Path: s.Path, RxBytes: rx, TxBytes: tx}, true
+}
+
+// deviceStateMsg turns a StateChanged signal of a Wi-Fi device into a
+// message for the connection history. Body is (new, old, reason).
+func deviceStateMsg(conn *dbus.Conn, s *dbus.Signal) (common.DeviceStateMsg, bool) {
+	if s.Name != network.DevIF+".StateChanged" || len(s.Body) < 3 {
+		return common.DeviceStateMsg{}, false
+	}
+	if v, err := conn.Object(network.NMDest, s.Path).GetProperty(network.DevIF + ".DeviceType"); err != nil || v.Value() != uint32(2) {
+		return common.DeviceStateMsg{}, false
+	}
+	msg := common.DeviceStateMsg{Path: s.Path}
+	msg.New, _ = s.Body[0].(uint32)
+	msg.Old, _ = s.Body[1].(uint32)
+	msg.Reason, _ = s.Body[2].(uint32)
+	// The network is only known while connecting, connected or leaving it
+	if msg.New >= common.DeviceStatePrepare && msg.New <= common.DeviceStateDeactivating {
+		msg.SSID, msg.BSSID, msg.Frequency = network.GetActiveNetwork(conn, s.Path)
+	}
+	if msg.New == common.DeviceStateActivated {
+		msg.IP = network.GetIPv4Address(conn, s.Path, network.DevIF)
+	}
+	return msg, true
+}
+
+// wireGuardStateMsg reports a StateChanged signal of a WireGuard device as a
+// VPN state change, since NetworkManager runs WireGuard profiles as devices
+// rather than through a VPN plugin.
+func wireGuardStateMsg(conn *dbus.Conn, s *dbus.Signal) (common.VpnStateMsg, bool) {
+	if s.Name != network.DevIF+".StateChanged" || len(s.Body) < 3 {
+		return common.VpnStateMsg{}, false
+	}
+	// 29 is NM_DEVICE_TYPE_WIREGUARD
+	if v, err := conn.Object(network.NMDest, s.Path).GetProperty(network.DevIF + ".DeviceType"); err != nil || v.Value() != uint32(29) {
+		return common.VpnStateMsg{}, false
+	}
+	state, _ := s.Body[0].(uint32)
+	msg := common.VpnStateMsg{WireGuard: true}
+	msg.Reason, _ = s.Body[2].(uint32)
+	switch state {
+	case common.DeviceStateActivated:
+		msg.State = common.VpnStateActivated
+		msg.IP = network.GetIPv4Address(conn, s.Path, network.DevIF)
+	case common.DeviceStateDeactivating:
+		// The profile is still attached here, unlike once disconnected
+		msg.State = common.VpnStateDisconnected
+	case common.DeviceStateFailed:
+		msg.State = common.VpnStateFailed
+	default:
+		return common.VpnStateMsg{}, false
+	}
+	msg.Name = network.GetActiveConnectionID(conn, s.Path)
+	if msg.Name == "" {
+		if v, err := conn.Object(network.NMDest, s.Path).GetProperty(network.DevIF + ".Interface"); err == nil {
+			msg.Name, _ = v.Value().(string)
+		}
+	}
+	return msg, true
+}
+
 // Command to periodically trigger a full data refresh.
 func RefreshTicker() tea.Cmd {
 	return tea.Tick(15*time.Second, func(t time.Time) tea.Msg {
@@ -129,5 +266,6 @@ func RefreshAllData(conn *dbus.Conn) tea.Cmd {
 		func() tea.Msg { return common.DeviceUpdateMsg(network.GetDevicesData(conn)) },
 		func() tea.Msg { return common.KnownNetworksUpdateMsg(network.GetKnownNetworks(conn)) },
 		func() tea.Msg { return common.VpnUpdateMsg(network.GetVpnData(conn)) },
+		func() tea.Msg { return common.ConnectivityUpdateMsg(network.GetConnectivity(conn)) },
 	)
 }
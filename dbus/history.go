@@ -0,0 +1,26 @@
+package dbus
+
+import (
+	"netpala/common"
+	"netpala/network"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// RecordHistoryCmd appends an event to the connection history.
+func RecordHistoryCmd(entry common.HistoryEntry) tea.Cmd {
+	return func() tea.Msg {
+		if err := network.AppendHistory(entry); err != nil {
+			return common.NoticeMsg(err.Error())
+		}
+		return nil
+	}
+}
+
+// LoadHistoryCmd reads the connection history for the history view.
+func LoadHistoryCmd() tea.Cmd {
+	return func() tea.Msg {
+		entries, err := network.LoadHistory()
+		return common.HistoryMsg{Entries: entries, Err: err}
+	}
+}
@@ -0,0 +1,23 @@
+package dbus
+
+import (
+	"fmt"
+
+	"netpala/common"
+	"netpala/network"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// RunHooksCmd runs the hook scripts of an event in the background. The event
+// is queued when the command is created, from Update, so hooks run in the
+// order their events arrived even though commands run concurrently.
+func RunHooksCmd(event common.HookEvent) tea.Cmd {
+	done := network.QueueHooks(event)
+	return func() tea.Msg {
+		if err := <-done; err != nil {
+			return common.NoticeMsg(fmt.Sprintf("Hook failed: %v (see %s)", err, network.HooksLog()))
+		}
+		return nil
+	}
+}
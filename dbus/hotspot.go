@@ -0,0 +1,129 @@
+package dbus
+
+import (
+	"fmt"
+	"time"
+
+	"netpala/common"
+	"netpala/network"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/godbus/dbus/v5"
+	"github.com/google/uuid"
+)
+
+// StartHotspotCmd creates (or updates) the netpala access point profile and
+// activates it on the given adapter.
+func StartHotspotCmd(conn *dbus.Conn, cfg common.HotspotConfig, dev common.Device) tea.Cmd {
+	return func() tea.Msg {
+		if !network.SupportsAP(dev) {
+			return common.ErrMsg{Err: fmt.Errorf("%s does not support access point mode", dev.Name)}
+		}
+		if cfg.SSID == "" {
+			return common.ErrMsg{Err: fmt.Errorf("hotspot SSID cannot be empty")}
+		}
+		if len(cfg.Password) < 8 || len(cfg.Password) > 63 {
+			return common.ErrMsg{Err: fmt.Errorf("hotspot password must be 8-63 characters")}
+		}
+		if cfg.Band == "a" && dev.Capabilities&network.WifiCapFreq5GHz == 0 {
+			return common.ErrMsg{Err: fmt.Errorf("%s does not support 5 GHz", dev.Name)}
+		}
+
+		// Reuse the existing profile's UUID so we don't pile up duplicates.
+		existingPath, existing := network.FindHotspotConnection(conn)
+		connUUID := ""
+		if existing != nil {
+			connUUID, _ = existing["connection"]["uuid"].Value().(string)
+		}
+		if connUUID == "" {
+			newUUID, err := uuid.NewRandom()
+			if err != nil {
+				return common.ErrMsg{Err: fmt.Errorf("failed to generate uuid: %w", err)}
+			}
+			connUUID = newUUID.String()
+		}
+
+		wireless := map[string]dbus.Variant{
+			"ssid": dbus.MakeVariant([]byte(cfg.SSID)),
+			"mode": dbus.MakeVariant("ap"),
+			"band": dbus.MakeVariant(cfg.Band),
+		}
+		if cfg.Channel != 0 {
+			wireless["channel"] = dbus.MakeVariant(cfg.Channel)
+		}
+
+		security := map[string]dbus.Variant{
+			"key-mgmt": dbus.MakeVariant("wpa-psk"),
+			"psk":      dbus.MakeVariant(cfg.Password),
+			"proto":    dbus.MakeVariant([]string{"rsn"}),
+			"pairwise": dbus.MakeVariant([]string{"ccmp"}),
+			"group":    dbus.MakeVariant([]string{"ccmp"}),
+		}
+		if cfg.Security == "wpa3-sae" {
+			security["key-mgmt"] = dbus.MakeVariant("sae")
+			security["pmf"] = dbus.MakeVariant(int32(3)) // required
+		}
+
+		settings := map[string]map[string]dbus.Variant{
+			"connection": {
+				"id":             dbus.MakeVariant(network.HotspotID),
+				"uuid":           dbus.MakeVariant(connUUID),
+				"type":           dbus.MakeVariant("802-11-wireless"),
+				"autoconnect":    dbus.MakeVariant(false),
+				"interface-name": dbus.MakeVariant(dev.Name),
+			},
+			"802-11-wireless":          wireless,
+			"802-11-wireless-security": security,
+			"ipv4":                     {"method": dbus.MakeVariant("shared")},
+			"ipv6":                     {"method": dbus.MakeVariant("ignore")},
+		}
+
+		connPath := existingPath
+		if existing != nil {
+			call := conn.Object(network.NMDest, existingPath).Call("org.freedesktop.NetworkManager.Settings.Connection.Update", 0, settings)
+			if call.Err != nil {
+				return common.ErrMsg{Err: fmt.Errorf("failed to update hotspot profile: %w", call.Err)}
+			}
+		} else {
+			settingsObj := conn.Object(network.NMDest, "/org/freedesktop/NetworkManager/Settings")
+			if err := settingsObj.Call("org.freedesktop.NetworkManager.Settings.AddConnection", 0, settings).Store(&connPath); err != nil {
+				return common.ErrMsg{Err: fmt.Errorf("failed to add hotspot profile: %w", err)}
+			}
+		}
+
+		return ConnectToNetworkCmd(conn, connPath, dev.Path)()
+	}
+}
+
+// StopHotspotCmd deactivates whatever connection is active on the adapter.
+func StopHotspotCmd(conn *dbus.Conn, devicePath dbus.ObjectPath) tea.Cmd {
+	return func() tea.Msg {
+		devObj := conn.Object(network.NMDest, devicePath)
+		activeVar, err := devObj.GetProperty(network.DevIF + ".ActiveConnection")
+		if err != nil {
+			return common.ErrMsg{Err: fmt.Errorf("failed to read active connection: %w", err)}
+		}
+		activePath, ok := activeVar.Value().(dbus.ObjectPath)
+		if !ok || activePath == "/" {
+			return nil
+		}
+
+		nm := conn.Object(network.NMDest, dbus.ObjectPath(network.NMPath))
+		if call := nm.Call(network.NMDest+".DeactivateConnection", 0, activePath); call.Err != nil {
+			return common.ErrMsg{Err: fmt.Errorf("failed to stop hotspot: %w", call.Err)}
+		}
+		// Success handled by signal listener
+		return nil
+	}
+}
+
+// HotspotClientsCmd polls the lease file for connected clients.
+func HotspotClientsCmd(iface string) tea.Cmd {
+	return tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
+		clients, err := network.GetHotspotClients(iface)
+		if err != nil {
+			return common.ErrMsg{Err: fmt.Errorf("failed to read hotspot leases: %w", err)}
+		}
+		return common.HotspotClientsUpdateMsg(clients)
+	})
+}
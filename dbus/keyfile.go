@@ -0,0 +1,133 @@
+package dbus
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"netpala/common"
+	"netpala/network"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/godbus/dbus/v5"
+	"github.com/google/uuid"
+)
+
+// ImportConnection parses a profile file and adds it to NetworkManager,
+// returning the new connection's id and any directives that were ignored.
+func ImportConnection(conn *dbus.Conn, file string) (string, []string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var settings map[string]map[string]dbus.Variant
+	var warnings []string
+	base := filepath.Base(file)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+	certDir := filepath.Join(common.DataDir(), "openvpn")
+	isOvpn := false
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".nmconnection":
+		settings, err = network.ParseKeyfile(data)
+	case ".conf":
+		settings, warnings, err = network.ParseWgQuick(name, data)
+	case ".ovpn":
+		dir, _ := filepath.Abs(filepath.Dir(file))
+		settings, warnings, err = network.ParseOvpn(name, data, dir, certDir)
+		isOvpn = true
+	default:
+		return "", nil, fmt.Errorf("don't know how to import '%s'", base)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse %s: %w", base, err)
+	}
+
+	id, err := AddConnection(conn, settings)
+	if err != nil && isOvpn {
+		// Don't leave the extracted certificates behind.
+		network.RemoveOvpnFiles(settings, certDir)
+	}
+	return id, warnings, err
+}
+
+// AddConnection saves a settings map, filling in the id and uuid if the
+// source didn't provide usable ones.
+func AddConnection(conn *dbus.Conn, settings map[string]map[string]dbus.Variant) (string, error) {
+	connSettings := settings["connection"]
+	if connSettings == nil {
+		connSettings = map[string]dbus.Variant{}
+		settings["connection"] = connSettings
+	}
+	if id, _ := connSettings["uuid"].Value().(string); uuid.Validate(id) != nil {
+		newUUID, err := uuid.NewRandom()
+		if err != nil {
+			return "", fmt.Errorf("failed to generate uuid: %w", err)
+		}
+		connSettings["uuid"] = dbus.MakeVariant(newUUID.String())
+	}
+	id, _ := connSettings["id"].Value().(string)
+
+	settingsObj := conn.Object(network.NMDest, "/org/freedesktop/NetworkManager/Settings")
+	call := settingsObj.Call("org.freedesktop.NetworkManager.Settings.AddConnection", 0, settings)
+	if call.Err != nil {
+		return "", fmt.Errorf("failed to add connection '%s': %w", id, call.Err)
+	}
+	return id, nil
+}
+
+// RestoreProfileCmd re-creates a deleted profile with its original UUID, so
+// anything referring to it (like VPN secondaries) keeps working.
+func RestoreProfileCmd(conn *dbus.Conn, snapshot common.ProfileSnapshot) tea.Cmd {
+	return func() tea.Msg {
+		if network.ConnectionExists(conn, snapshot.UUID) {
+			// A checkpoint rollback already brought it back
+			return common.NoticeMsg(fmt.Sprintf("'%s' already exists", snapshot.ID))
+		}
+		if _, err := AddConnection(conn, snapshot.Settings); err != nil {
+			return common.UndoFailedMsg{Snapshot: snapshot, Err: err}
+		}
+		return common.NoticeMsg(fmt.Sprintf("Restored '%s'", snapshot.ID))
+	}
+}
+
+// ImportConnectionCmd imports a profile file from the interactive UI.
+func ImportConnectionCmd(conn *dbus.Conn, file string) tea.Cmd {
+	return func() tea.Msg {
+		id, warnings, err := ImportConnection(conn, expandHome(file))
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		if len(warnings) > 0 {
+			return common.NoticeMsg(fmt.Sprintf("Imported '%s' (ignored: %s)", id, strings.Join(warnings, ", ")))
+		}
+		return common.NoticeMsg(fmt.Sprintf("Imported '%s'", id))
+	}
+}
+
+// ExportConnectionCmd writes a saved connection to <dir>/<id>.nmconnection.
+func ExportConnectionCmd(conn *dbus.Conn, connectionPath dbus.ObjectPath, dir string) tea.Cmd {
+	return func() tea.Msg {
+		id, data, err := network.ExportKeyfile(conn, connectionPath)
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		file := filepath.Join(dir, strings.ReplaceAll(id, "/", "_")+".nmconnection")
+		// Profiles contain secrets, keep them private like NetworkManager does.
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return common.ErrMsg{Err: fmt.Errorf("failed to export '%s': %w", id, err)}
+		}
+		if err := os.WriteFile(file, []byte(data), 0600); err != nil {
+			return common.ErrMsg{Err: fmt.Errorf("failed to export '%s': %w", id, err)}
+		}
+		return common.NoticeMsg(fmt.Sprintf("Exported '%s' to %s", id, file))
+	}
+}
+
+func expandHome(path string) string {
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(path, "~/") {
+		return filepath.Join(home, path[2:])
+	}
+	return path
+}
@@ -0,0 +1,51 @@
+package dbus
+
+import (
+	"netpala/common"
+	"netpala/network"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/godbus/dbus/v5"
+)
+
+// ToggleAirplaneModeCmd turns every radio off, remembering which were on, or
+// turns them back on. Without a remembered state (airplane mode was turned on
+// elsewhere) everything is switched on.
+func ToggleAirplaneModeCmd(conn *dbus.Conn, restore *common.RadioState) tea.Cmd {
+	return func() tea.Msg {
+		current := network.GetRadioState(conn)
+		if current.AnyOn() {
+			off := common.RadioState{Bluetooth: map[dbus.ObjectPath]bool{}}
+			for path := range current.Bluetooth {
+				off.Bluetooth[path] = false
+			}
+			msg := common.AirplaneModeMsg{Enabled: true, Previous: current}
+			if err := network.SetRadioState(conn, off); err != nil {
+				// Still remember what was on so it can be restored
+				return tea.BatchMsg{
+					func() tea.Msg { return msg },
+					func() tea.Msg { return common.NoticeMsg("Airplane mode incomplete: " + err.Error()) },
+				}
+			}
+			return msg
+		}
+
+		target := common.RadioState{Wireless: true, Wwan: true, Bluetooth: map[dbus.ObjectPath]bool{}}
+		for path := range current.Bluetooth {
+			target.Bluetooth[path] = true
+		}
+		if restore != nil {
+			target.Wireless, target.Wwan = restore.Wireless, restore.Wwan
+			for path := range target.Bluetooth {
+				// Adapters plugged in since then are left on
+				if on, ok := restore.Bluetooth[path]; ok {
+					target.Bluetooth[path] = on
+				}
+			}
+		}
+		if err := network.SetRadioState(conn, target); err != nil {
+			return common.NoticeMsg("Couldn't restore all radios: " + err.Error())
+		}
+		return common.AirplaneModeMsg{Enabled: false}
+	}
+}
@@ -0,0 +1,32 @@
+package dbus
+
+import (
+	"netpala/common"
+	"netpala/network"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/godbus/dbus/v5"
+)
+
+// ShowWifiQRCmd fetches a known network's secrets and builds its QR payload.
+func ShowWifiQRCmd(conn *dbus.Conn, connectionPath dbus.ObjectPath) tea.Cmd {
+	return func() tea.Msg {
+		ssid, payload, err := network.GetWifiQRPayload(conn, connectionPath)
+		if err != nil {
+			// Open or enterprise networks and missing secrets aren't fatal
+			return common.NoticeMsg(err.Error())
+		}
+		return common.WifiQRMsg{SSID: ssid, Payload: payload}
+	}
+}
+
+// RevealSecretCmd fetches the saved password of a known network.
+func RevealSecretCmd(conn *dbus.Conn, connectionPath dbus.ObjectPath, ssid string) tea.Cmd {
+	return func() tea.Msg {
+		label, secret, err := network.GetSavedSecret(conn, connectionPath)
+		if err != nil {
+			return common.NoticeMsg(err.Error())
+		}
+		return common.SecretMsg{SSID: ssid, Label: label, Secret: secret}
+	}
+}
@@ -0,0 +1,31 @@
+package dbus
+
+import (
+	"netpala/common"
+	"netpala/network"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/godbus/dbus/v5"
+)
+
+// EnableStatisticsCmd asks NetworkManager to publish the byte counters of a
+// device, which then arrive as PropertiesChanged signals.
+func EnableStatisticsCmd(conn *dbus.Conn, path dbus.ObjectPath) tea.Cmd {
+	return func() tea.Msg {
+		prev, err := network.SetStatisticsRefreshRate(conn, path, network.StatisticsRefreshMs)
+		if err != nil {
+			return common.NoticeMsg("Live throughput unavailable: " + err.Error())
+		}
+		rx, tx := network.GetStatistics(conn, path)
+		return common.StatisticsEnabledMsg{Path: path, PrevRateMs: prev, RxBytes: rx, TxBytes: tx}
+	}
+}
+
+// RestoreStatisticsCmd puts back the refresh rate a device had before
+// EnableStatisticsCmd, once we stop watching it.
+func RestoreStatisticsCmd(conn *dbus.Conn, path dbus.ObjectPath, rateMs uint32) tea.Cmd {
+	return func() tea.Msg {
+		network.SetStatisticsRefreshRate(conn, path, rateMs)
+		return nil
+	}
+}
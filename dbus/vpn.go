@@ -0,0 +1,72 @@
+package dbus
+
+import (
+	"fmt"
+
+	"netpala/common"
+	"netpala/network"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/godbus/dbus/v5"
+)
+
+// updateConnection applies edit to a saved profile's settings and writes
+// them back. Secrets left out of the update are kept by NetworkManager.
+func updateConnection(conn *dbus.Conn, connectionPath dbus.ObjectPath, edit func(map[string]map[string]dbus.Variant)) error {
+	connObj := conn.Object(network.NMDest, connectionPath)
+	var settings map[string]map[string]dbus.Variant
+	if err := connObj.Call("org.freedesktop.NetworkManager.Settings.Connection.GetSettings", 0).Store(&settings); err != nil {
+		return fmt.Errorf("failed to read connection: %w", err)
+	}
+	edit(settings)
+	if call := connObj.Call("org.freedesktop.NetworkManager.Settings.Connection.Update", 0, settings); call.Err != nil {
+		return fmt.Errorf("failed to update connection: %w", call.Err)
+	}
+	return nil
+}
+
+// RenameConnectionCmd changes the id of a saved profile.
+func RenameConnectionCmd(conn *dbus.Conn, connectionPath dbus.ObjectPath, name string) tea.Cmd {
+	return func() tea.Msg {
+		if name == "" {
+			return common.ChangeFailedMsg{Err: fmt.Errorf("connection name can't be empty")}
+		}
+		err := updateConnection(conn, connectionPath, func(settings map[string]map[string]dbus.Variant) {
+			settings["connection"]["id"] = dbus.MakeVariant(name)
+		})
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		// Success handled by signal listener
+		return nil
+	}
+}
+
+// SetAutoconnectCmd turns autoconnect on or off for a saved profile.
+func SetAutoconnectCmd(conn *dbus.Conn, connectionPath dbus.ObjectPath, enable bool) tea.Cmd {
+	return func() tea.Msg {
+		err := updateConnection(conn, connectionPath, func(settings map[string]map[string]dbus.Variant) {
+			settings["connection"]["autoconnect"] = dbus.MakeVariant(enable)
+		})
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		// Success handled by signal listener
+		return nil
+	}
+}
+
+// SetSecondariesCmd sets the VPNs NetworkManager brings up together with a
+// saved connection.
+func SetSecondariesCmd(conn *dbus.Conn, connectionPath dbus.ObjectPath, uuids []string) tea.Cmd {
+	return func() tea.Msg {
+		err := updateConnection(conn, connectionPath, func(settings map[string]map[string]dbus.Variant) {
+			settings["connection"]["secondaries"] = dbus.MakeVariant(uuids)
+		})
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		// Success handled by signal listener
+		return nil
+	}
+}
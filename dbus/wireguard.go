@@ -0,0 +1,45 @@
+package dbus
+
+import (
+	"fmt"
+
+	"netpala/common"
+	"netpala/network"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/godbus/dbus/v5"
+)
+
+// LoadWireGuardCmd reads a WireGuard profile for the editor.
+func LoadWireGuardCmd(conn *dbus.Conn, connectionPath dbus.ObjectPath) tea.Cmd {
+	return func() tea.Msg {
+		name, cfg, err := network.GetWireGuardConfig(conn, connectionPath)
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return common.WireGuardLoadedMsg{Path: connectionPath, Name: name, Config: cfg}
+	}
+}
+
+// SaveWireGuardCmd replaces the wireguard setting of a saved profile.
+func SaveWireGuardCmd(conn *dbus.Conn, connectionPath dbus.ObjectPath, cfg common.WireGuardConfig) tea.Cmd {
+	return func() tea.Msg {
+		wg, err := network.WireGuardSetting(cfg)
+		if err != nil {
+			return common.ChangeFailedMsg{Err: err}
+		}
+
+		connObj := conn.Object(network.NMDest, connectionPath)
+		var settings map[string]map[string]dbus.Variant
+		if err := connObj.Call("org.freedesktop.NetworkManager.Settings.Connection.GetSettings", 0).Store(&settings); err != nil {
+			return common.ErrMsg{Err: fmt.Errorf("failed to read connection: %w", err)}
+		}
+		settings["wireguard"] = wg
+
+		if call := connObj.Call("org.freedesktop.NetworkManager.Settings.Connection.Update", 0, settings); call.Err != nil {
+			return common.ErrMsg{Err: fmt.Errorf("failed to save WireGuard settings: %w", call.Err)}
+		}
+		id, _ := settings["connection"]["id"].Value().(string)
+		return common.NoticeMsg(fmt.Sprintf("Saved '%s'", id))
+	}
+}
@@ -0,0 +1,40 @@
+package dbus
+
+import (
+	"fmt"
+
+	"netpala/common"
+	"netpala/network"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/godbus/dbus/v5"
+)
+
+// LoadWirelessCmd reads the advanced Wi-Fi settings of a profile for the editor.
+func LoadWirelessCmd(conn *dbus.Conn, connectionPath dbus.ObjectPath) tea.Cmd {
+	return func() tea.Msg {
+		name, cfg, err := network.GetWirelessConfig(conn, connectionPath)
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return common.WirelessLoadedMsg{Path: connectionPath, Name: name, Config: cfg}
+	}
+}
+
+// SaveWirelessCmd writes the advanced Wi-Fi settings back to a profile.
+func SaveWirelessCmd(conn *dbus.Conn, connectionPath dbus.ObjectPath, cfg common.WirelessConfig) tea.Cmd {
+	return func() tea.Msg {
+		if err := network.ValidateWirelessConfig(cfg); err != nil {
+			return common.ChangeFailedMsg{Err: err}
+		}
+		var id string
+		err := updateConnection(conn, connectionPath, func(settings map[string]map[string]dbus.Variant) {
+			id, _ = settings["connection"]["id"].Value().(string)
+			network.ApplyWirelessConfig(settings, cfg)
+		})
+		if err != nil {
+			return common.ErrMsg{Err: err}
+		}
+		return common.NoticeMsg(fmt.Sprintf("Saved '%s'", id))
+	}
+}
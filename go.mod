@@ -3,6 +3,7 @@ module netpala
 go 1.25.1
 
 require (
+	github.com/atotto/clipboard v0.1.4
 	github.com/charmbracelet/bubbles v0.21.0
 	github.com/charmbracelet/bubbletea v1.3.10
 	github.com/charmbracelet/lipgloss v1.1.0
@@ -12,8 +13,6 @@ require (
 	golang.org/x/term v0.35.0
 )
 
-require github.com/atotto/clipboard v0.1.4 // indirect
-
 require (
 	github.com/aymanbagabas/go-osc52/v2 v2.0.1 // indirect
 	github.com/charmbracelet/colorprofile v0.3.2 // indirect
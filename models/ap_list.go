@@ -0,0 +1,161 @@
+package models
+
+import (
+	"fmt"
+	"netpala/common"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// AccessPointList shows every BSSID of a network and lets one be joined or
+// pinned.
+type AccessPointList struct {
+	SSID    string
+	Known   bool   // pinning only applies to saved profiles
+	Pinned  string // BSSID the profile is locked to
+	Current string // BSSID the device is associated with
+	aps     []common.AccessPoint
+	history common.SignalHistory
+	cursor  int
+}
+
+func ModelAccessPointList(ssid string, aps []common.AccessPoint, history common.SignalHistory, known bool, pinned, current string) AccessPointList {
+	return AccessPointList{SSID: ssid, Known: known, Pinned: pinned, Current: current, aps: aps, history: history}
+}
+
+func (m AccessPointList) Init() tea.Cmd {
+	return nil
+}
+
+func (m AccessPointList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch key := msg.(type) {
+	case tea.KeyMsg:
+		switch key.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.aps)-1 {
+				m.cursor++
+			}
+		case "enter":
+			if len(m.aps) > 0 {
+				ap := m.aps[m.cursor]
+				return m, func() tea.Msg { return common.ConnectAccessPointMsg{AP: ap} }
+			}
+		case "p":
+			if m.Known && len(m.aps) > 0 {
+				bssid := m.aps[m.cursor].BSSID
+				if strings.EqualFold(bssid, m.Pinned) {
+					bssid = ""
+				}
+				return m, func() tea.Msg { return common.PinBssidMsg{BSSID: bssid} }
+			}
+		case "esc", "ctrl+c", "q":
+			return m, func() tea.Msg { return common.ExitFormMsg{} }
+		}
+	}
+	return m, nil
+}
+
+func (m AccessPointList) View() string {
+	containerStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#9cca69")).
+		Foreground(lipgloss.Color("#a7abca")).
+		Padding(0, 1)
+
+	activeStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#cda162"))
+
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#444a66"))
+
+	row := "%-2s %-17s  %-7s  %4s  %6s  %9s  %6s  %5s  %-9s %s"
+	lines := []string{
+		fmt.Sprintf("Access points of '%s'", m.SSID),
+		"",
+		headerStyle.Render(fmt.Sprintf(row, "", "BSSID", "Band", "Ch", "Width", "Rate", "Signal", "Seen", "Ciphers", "")),
+	}
+	if len(m.aps) == 0 {
+		lines = append(lines, "Not in range, press 'r' in the main view to scan")
+	}
+	for i, ap := range m.aps {
+		mark := ""
+		if strings.EqualFold(ap.BSSID, m.Current) {
+			mark = "●"
+		}
+		note := ""
+		if strings.EqualFold(ap.BSSID, m.Pinned) {
+			note = "pinned"
+		}
+		channel := "-"
+		if ch := common.FreqToChannel(ap.Frequency); ch > 0 {
+			channel = fmt.Sprint(ch)
+		}
+		width := "-"
+		if ap.Bandwidth > 0 {
+			width = fmt.Sprintf("%dMHz", ap.Bandwidth)
+		}
+		line := fmt.Sprintf(row,
+			mark, ap.BSSID, common.FreqToBand(ap.Frequency), channel, width,
+			fmt.Sprintf("%d Mb/s", ap.MaxBitrate/1000), fmt.Sprintf("%d%%", ap.Signal),
+			seenAgo(ap.LastSeen), ap.Security.CipherString(), note)
+		if i == m.cursor {
+			line = activeStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(m.aps) > 0 {
+		lines = append(lines, "", m.signalView(m.aps[m.cursor].BSSID, headerStyle))
+	}
+
+	help := "enter: connect via this AP • esc: close"
+	if m.Known {
+		help = "enter: connect via this AP • p: pin/unpin • esc: close"
+	}
+	lines = append(lines, "", help)
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// signalView charts the signal history of the selected access point.
+func (m AccessPointList) signalView(bssid string, axisStyle lipgloss.Style) string {
+	samples := m.history.Samples(bssid)
+	if len(samples) < 2 {
+		return axisStyle.Render("Collecting signal history for " + bssid + "...")
+	}
+	lo, avg, hi := common.SignalStats(samples)
+	chart := common.SignalChart(samples, 60, 4)
+	axis := []string{"100%", "", "", "  0%"}
+	lines := []string{fmt.Sprintf("Signal of %s  min %d%%  avg %d%%  max %d%%", bssid, lo, avg, hi)}
+	for i, row := range chart {
+		lines = append(lines, axisStyle.Render(fmt.Sprintf("%4s │", axis[i]))+row)
+	}
+	span := samples[len(samples)-1].At.Sub(samples[max(len(samples)-60, 0)].At)
+	lines = append(lines, axisStyle.Render(fmt.Sprintf("     └ last %s", span.Round(time.Second))))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// seenAgo renders how long ago an access point was last seen in a scan.
+func seenAgo(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	age := time.Since(t)
+	switch {
+	case age < 5*time.Second:
+		return "now"
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	}
+	return fmt.Sprintf("%dh", int(age.Hours()))
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"fmt"
+	"netpala/common"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const channelBarWidth = 30
+
+// ChannelView charts how crowded each Wi-Fi channel is.
+type ChannelView struct {
+	usage   map[string][]common.ChannelUsage
+	suggest map[string]int // hotspot suggestion per band
+	band    int
+}
+
+func ModelChannelView(aps []common.AccessPoint, currentFreq int) ChannelView {
+	usage := common.AnalyzeChannels(aps, currentFreq)
+	m := ChannelView{
+		usage: usage,
+		suggest: map[string]int{
+			"2.4 GHz": common.SuggestHotspotChannel(usage, "bg"),
+			"5 GHz":   common.SuggestHotspotChannel(usage, "a"),
+		},
+	}
+	// Start on the band we're connected on
+	for i, band := range common.Bands {
+		if band == common.FreqToBand(currentFreq) {
+			m.band = i
+		}
+	}
+	return m
+}
+
+func (m ChannelView) Init() tea.Cmd {
+	return nil
+}
+
+func (m ChannelView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch key := msg.(type) {
+	case tea.KeyMsg:
+		switch key.String() {
+		case "tab", "right", "l":
+			m.band = (m.band + 1) % len(common.Bands)
+		case "shift+tab", "left", "h":
+			m.band = (m.band + len(common.Bands) - 1) % len(common.Bands)
+		case "esc", "ctrl+c", "q", "enter":
+			return m, func() tea.Msg { return common.ExitFormMsg{} }
+		}
+	}
+	return m, nil
+}
+
+func (m ChannelView) View() string {
+	containerStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#9cca69")).
+		Foreground(lipgloss.Color("#a7abca")).
+		Padding(0, 1)
+
+	activeStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#cda162"))
+
+	barStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#9cca69"))
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#444a66"))
+
+	var tabs []string
+	for i, band := range common.Bands {
+		if i == m.band {
+			tabs = append(tabs, activeStyle.Render("["+band+"]"))
+		} else {
+			tabs = append(tabs, " "+band+" ")
+		}
+	}
+
+	band := common.Bands[m.band]
+	channels := m.usage[band]
+	maxLoad := 1.0
+	for _, u := range channels {
+		maxLoad = max(maxLoad, u.Load)
+	}
+
+	lines := []string{"Channel congestion  " + strings.Join(tabs, " "), ""}
+	for _, u := range channels {
+		filled := int(u.Load / maxLoad * channelBarWidth)
+		if u.Load > 0 && filled == 0 {
+			filled = 1
+		}
+		bar := barStyle.Render(strings.Repeat("█", filled)) +
+			hintStyle.Render(strings.Repeat("░", channelBarWidth-filled))
+
+		aps := ""
+		if u.APs > 0 {
+			aps = fmt.Sprintf("%d AP", u.APs)
+			if u.APs > 1 {
+				aps += "s"
+			}
+		}
+		note := ""
+		if u.Channel == m.suggest[band] {
+			note = "★ best for hotspot"
+		}
+
+		label := fmt.Sprintf("%4d", u.Channel)
+		if u.Current {
+			label = activeStyle.Render(fmt.Sprintf("●%3d", u.Channel))
+			note = strings.TrimSpace(activeStyle.Render("connected") + " " + note)
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %-6s %s", label, bar, aps, note))
+	}
+	if band == "6 GHz" {
+		lines = append(lines, "", hintStyle.Render("Only preferred scanning channels and occupied ones are listed"))
+	}
+
+	suggestion := "Hotspot: "
+	if ch := m.suggest["2.4 GHz"]; ch > 0 {
+		suggestion += fmt.Sprintf("channel %d on 2.4 GHz", ch)
+	}
+	if ch := m.suggest["5 GHz"]; ch > 0 {
+		suggestion += fmt.Sprintf(", channel %d on 5 GHz", ch)
+	}
+	lines = append(lines, "", suggestion, hintStyle.Render("←/→ switch band • esc close"))
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
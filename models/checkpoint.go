@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+	"netpala/common"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// CheckpointPrompt asks whether a change should be kept, rolling it back
+// when the countdown runs out.
+type CheckpointPrompt struct {
+	Description string
+	Deadline    time.Time
+	keep        bool
+}
+
+func ModelCheckpointPrompt(description string, deadline time.Time) CheckpointPrompt {
+	return CheckpointPrompt{Description: description, Deadline: deadline}
+}
+
+func (m CheckpointPrompt) Init() tea.Cmd {
+	return nil
+}
+
+func (m CheckpointPrompt) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case common.CheckpointTickMsg:
+		if !time.Now().Before(m.Deadline) {
+			return m, func() tea.Msg { return common.SubmitCheckpointMsg{Keep: false} }
+		}
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "enter":
+			keep := m.keep
+			return m, func() tea.Msg { return common.SubmitCheckpointMsg{Keep: keep} }
+		case "esc", "ctrl+c":
+			return m, func() tea.Msg { return common.SubmitCheckpointMsg{Keep: false} }
+		case "tab", "right":
+			m.keep = true
+		case "shift+tab", "left":
+			m.keep = false
+		}
+	}
+	return m, nil
+}
+
+func (m CheckpointPrompt) View() string {
+	containerStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#cda162")).
+		Foreground(lipgloss.Color("#a7abca")).
+		Align(lipgloss.Center).
+		Padding(0, 1).
+		Width(50)
+
+	inactiveBorderStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#444a66")).
+		Align(lipgloss.Center).
+		Padding(0, 3).
+		Width(18)
+
+	activeBorderStyle := inactiveBorderStyle.
+		BorderForeground(lipgloss.Color("#cda162"))
+
+	countdownStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#cda162"))
+
+	remaining := max(int(time.Until(m.Deadline).Round(time.Second).Seconds()), 0)
+
+	keepButton := inactiveBorderStyle.Render("Keep")
+	revertButton := activeBorderStyle.Render("Revert")
+	if m.keep {
+		keepButton = activeBorderStyle.Render("Keep")
+		revertButton = inactiveBorderStyle.Render("Revert")
+	}
+
+	return containerStyle.Render(
+		lipgloss.JoinVertical(lipgloss.Center,
+			fmt.Sprintf("Applied: %s", m.Description),
+			"Is everything still working?",
+			countdownStyle.Render(fmt.Sprintf("Rolling back in %ds", remaining)),
+			lipgloss.JoinHorizontal(lipgloss.Center, revertButton, keepButton),
+		),
+	)
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"fmt"
+	"netpala/common"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// How many recent events of the selected network are listed.
+const historyRecentEvents = 8
+
+// HistoryView shows per-network connection statistics and the latest
+// events of the selected network.
+type HistoryView struct {
+	entries []common.HistoryEntry
+	stats   []common.NetworkStats
+	cursor  int
+}
+
+func ModelHistoryView(entries []common.HistoryEntry) HistoryView {
+	return HistoryView{entries: entries, stats: common.HistoryStats(entries)}
+}
+
+func (m HistoryView) Init() tea.Cmd {
+	return nil
+}
+
+func (m HistoryView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch key := msg.(type) {
+	case tea.KeyMsg:
+		switch key.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.stats)-1 {
+				m.cursor++
+			}
+		case "esc", "ctrl+c", "q", "enter":
+			return m, func() tea.Msg { return common.ExitFormMsg{} }
+		}
+	}
+	return m, nil
+}
+
+func (m HistoryView) View() string {
+	containerStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#9cca69")).
+		Foreground(lipgloss.Color("#a7abca")).
+		Padding(0, 1)
+
+	activeStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#cda162"))
+
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#444a66"))
+
+	failedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#e06c75"))
+
+	row := "%-24.24s  %8s  %8s  %7s  %11s  %-16s"
+	lines := []string{
+		"Connection history",
+		"",
+		headerStyle.Render(fmt.Sprintf(row, "Network", "Connects", "Failures", "Success", "Avg session", "Last used")),
+	}
+	if len(m.stats) == 0 {
+		lines = append(lines, "Nothing recorded yet")
+	}
+	for i, s := range m.stats {
+		line := fmt.Sprintf(row,
+			s.SSID, fmt.Sprint(s.Connects), fmt.Sprint(s.Failures), fmt.Sprintf("%d%%", s.SuccessRate()),
+			common.FormatDuration(s.AvgSession), s.LastSeen.Local().Format("2006-01-02 15:04"))
+		if i == m.cursor {
+			line = activeStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(m.stats) > 0 {
+		ssid := m.stats[m.cursor].SSID
+		lines = append(lines, "", fmt.Sprintf("Recent events on '%s'", ssid))
+		shown := 0
+		for i := len(m.entries) - 1; i >= 0 && shown < historyRecentEvents; i-- {
+			e := m.entries[i]
+			if e.SSID != ssid {
+				continue
+			}
+			shown++
+			detail := e.BSSID
+			if e.Band != "" {
+				detail += " " + e.Band
+			}
+			switch e.Event {
+			case "disconnected":
+				detail = fmt.Sprintf("after %s", common.FormatDuration(time.Duration(e.Duration)*time.Second))
+				if e.Reason != "" {
+					detail += ", " + e.Reason
+				}
+			case "failed":
+				detail = e.Reason
+			}
+			line := fmt.Sprintf("%s  %-12s  %s", e.Time.Local().Format("2006-01-02 15:04"), e.Event, detail)
+			if e.Event == "failed" {
+				line = failedStyle.Render(line)
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	lines = append(lines, "", headerStyle.Render("↑/↓ select network • esc close"))
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
@@ -0,0 +1,266 @@
+package models
+
+import (
+	"fmt"
+	"netpala/common"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var hotspotBands = []struct{ Value, Label string }{
+	{"bg", "2.4 GHz"},
+	{"a", "5 GHz"},
+}
+
+var hotspotSecurity = []struct{ Value, Label string }{
+	{"wpa2-psk", "WPA2"},
+	{"wpa3-sae", "WPA3"},
+}
+
+type HotspotForm struct {
+	SSID     textinput.Model
+	Password textinput.Model
+	Channel  textinput.Model
+	band     int
+	security int
+	focused  int
+
+	Device  common.Device
+	Running bool
+	Clients []common.HotspotClient
+}
+
+func ModelHotspotForm() HotspotForm {
+	SSID := textinput.New()
+	SSID.Placeholder = "SSID"
+	SSID.Prompt = ""
+	SSID.Width = 32
+	SSID.CharLimit = 32
+	SSID.Focus()
+
+	Password := textinput.New()
+	Password.Placeholder = "8-63 characters (ctrl+g to generate)"
+	Password.Prompt = ""
+	Password.Width = 32
+	Password.CharLimit = 63
+
+	Channel := textinput.New()
+	Channel.Placeholder = "auto"
+	Channel.Prompt = ""
+	Channel.Width = 32
+	Channel.CharLimit = 3
+	Channel.Validate = func(s string) error {
+		if s == "" {
+			return nil
+		}
+		_, err := strconv.ParseUint(s, 10, 32)
+		return err
+	}
+
+	hostname, _ := os.Hostname()
+	SSID.SetValue(hostname)
+
+	return HotspotForm{
+		SSID:     SSID,
+		Password: Password,
+		Channel:  Channel,
+		focused:  0,
+	}
+}
+
+// SetConfig pre-fills the form from an existing hotspot profile.
+func (m *HotspotForm) SetConfig(cfg common.HotspotConfig) {
+	m.SSID.SetValue(cfg.SSID)
+	m.Password.SetValue(cfg.Password)
+	if cfg.Channel != 0 {
+		m.Channel.SetValue(strconv.FormatUint(uint64(cfg.Channel), 10))
+	}
+	for i, b := range hotspotBands {
+		if b.Value == cfg.Band {
+			m.band = i
+		}
+	}
+	for i, s := range hotspotSecurity {
+		if s.Value == cfg.Security {
+			m.security = i
+		}
+	}
+}
+
+func (m HotspotForm) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m HotspotForm) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case common.HotspotClientsUpdateMsg:
+		m.Clients = msg
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "tab", "shift+tab", "up", "down":
+			if msg.String() == "shift+tab" || msg.String() == "up" {
+				m.focused = (m.focused + 5) % 6
+			} else {
+				m.focused = (m.focused + 1) % 6
+			}
+
+			m.SSID.Blur()
+			m.Password.Blur()
+			m.Channel.Blur()
+			switch m.focused {
+			case 0:
+				m.SSID.Focus()
+			case 1:
+				m.Password.Focus()
+			case 3:
+				m.Channel.Focus()
+			}
+			return m, nil
+		case "left", "right":
+			switch m.focused {
+			case 2:
+				m.band = (m.band + 1) % len(hotspotBands)
+				return m, nil
+			case 4:
+				m.security = (m.security + 1) % len(hotspotSecurity)
+				return m, nil
+			}
+		case "ctrl+g":
+			m.Password.SetValue(common.GeneratePassword(12))
+			return m, nil
+		case "enter":
+			if m.focused != 5 {
+				break
+			}
+			if m.Running {
+				return m, func() tea.Msg { return common.StopHotspotMsg{} }
+			}
+			channel, _ := strconv.ParseUint(m.Channel.Value(), 10, 32)
+			config := common.HotspotConfig{
+				SSID:     m.SSID.Value(),
+				Password: m.Password.Value(),
+				Band:     hotspotBands[m.band].Value,
+				Channel:  uint32(channel),
+				Security: hotspotSecurity[m.security].Value,
+			}
+			return m, func() tea.Msg { return common.SubmitHotspotFormMsg{Config: config} }
+		case "esc", "ctrl+c":
+			return m, func() tea.Msg { return common.ExitFormMsg{} }
+		}
+	}
+
+	m.SSID, cmd = m.SSID.Update(msg)
+	cmds = append(cmds, cmd)
+	m.Password, cmd = m.Password.Update(msg)
+	cmds = append(cmds, cmd)
+	m.Channel, cmd = m.Channel.Update(msg)
+	cmds = append(cmds, cmd)
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m HotspotForm) View() string {
+	inactiveBorderStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#444a66")).
+		Padding(0, 1)
+
+	activeBorderStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#a7abca")).
+		Padding(0, 1)
+
+	inactiveLabelStyle := lipgloss.NewStyle().
+		Bold(false).
+		Foreground(lipgloss.Color("#a7abca"))
+
+	activeLabelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#cda162"))
+
+	formStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#9cca69")).
+		Padding(0, 1)
+
+	label := func(idx int, text string) string {
+		if m.focused == idx {
+			return activeLabelStyle.Render(text)
+		}
+		return inactiveLabelStyle.Render(text)
+	}
+	box := func(idx int, content string) string {
+		if m.focused == idx {
+			return activeBorderStyle.Render(content)
+		}
+		return inactiveBorderStyle.Render(content)
+	}
+	choice := func(idx int, options []struct{ Value, Label string }, selected int) string {
+		var parts []string
+		for i, option := range options {
+			if i == selected {
+				parts = append(parts, activeLabelStyle.Render("» "+option.Label))
+			} else {
+				parts = append(parts, inactiveLabelStyle.Render("  "+option.Label))
+			}
+		}
+		return box(idx, lipgloss.NewStyle().Width(32).Render(strings.Join(parts, "  ")))
+	}
+
+	status := "stopped"
+	buttonText := "Start Hotspot"
+	if m.Running {
+		status = "running"
+		buttonText = "Stop Hotspot"
+	}
+
+	submitLabel := inactiveBorderStyle.
+		Width(36).
+		Align(lipgloss.Center).
+		Render(buttonText)
+	if m.focused == 5 {
+		submitLabel = activeBorderStyle.
+			Width(36).
+			Bold(true).
+			Align(lipgloss.Center).
+			BorderForeground(lipgloss.Color("#cda162")).
+			Render(buttonText)
+	}
+
+	clients := []string{inactiveLabelStyle.Render(fmt.Sprintf("\nClients (%d):", len(m.Clients)))}
+	for _, c := range m.Clients {
+		clients = append(clients, inactiveLabelStyle.Render(fmt.Sprintf(" %-15s %s  %s", c.IP, c.MAC, c.Hostname)))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		inactiveLabelStyle.Render(fmt.Sprintf("Hotspot on %s (%s)", m.Device.Name, status)),
+
+		label(0, "\nSSID:"),
+		box(0, m.SSID.View()),
+
+		label(1, "\nPassword:"),
+		box(1, m.Password.View()),
+
+		label(2, "\nBand (←/→):"),
+		choice(2, hotspotBands, m.band),
+
+		label(3, "\nChannel:"),
+		box(3, m.Channel.View()),
+
+		label(4, "\nSecurity (←/→):"),
+		choice(4, hotspotSecurity, m.security),
+
+		submitLabel,
+		lipgloss.JoinVertical(lipgloss.Left, clients...),
+	)
+	return formStyle.Render(content)
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"fmt"
+	"netpala/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type QRView struct {
+	SSID    string
+	Payload string
+}
+
+func ModelQRView(ssid, payload string) QRView {
+	return QRView{SSID: ssid, Payload: payload}
+}
+
+func (m QRView) Init() tea.Cmd {
+	return nil
+}
+
+func (m QRView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch key := msg.(type) {
+	case tea.KeyMsg:
+		switch key.String() {
+		case "esc", "ctrl+c", "enter", "q":
+			return m, func() tea.Msg { return common.ExitFormMsg{} }
+		}
+	}
+	return m, nil
+}
+
+func (m QRView) View() string {
+	containerStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#9cca69")).
+		Foreground(lipgloss.Color("#a7abca")).
+		Align(lipgloss.Center).
+		Padding(0, 1)
+
+	modules, err := common.EncodeQR(m.Payload)
+	if err != nil {
+		return containerStyle.Render(err.Error())
+	}
+
+	return containerStyle.Render(
+		lipgloss.JoinVertical(lipgloss.Center,
+			fmt.Sprintf("Scan to join '%s'", m.SSID),
+			"",
+			// Force plain white so the code keeps its contrast on any theme.
+			lipgloss.NewStyle().Foreground(lipgloss.Color("#ffffff")).Background(lipgloss.Color("#000000")).Render(common.RenderQR(modules)),
+			"",
+			"esc: close",
+		),
+	)
+}
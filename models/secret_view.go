@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+	"netpala/common"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type SecretView struct {
+	SSID     string
+	Label    string
+	Secret   string
+	Revealed bool
+	status   string
+}
+
+func ModelSecretView(ssid, label, secret string) SecretView {
+	return SecretView{SSID: ssid, Label: label, Secret: secret}
+}
+
+func (m SecretView) Init() tea.Cmd {
+	return nil
+}
+
+func (m SecretView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch key := msg.(type) {
+	case tea.KeyMsg:
+		switch key.String() {
+		case "esc", "ctrl+c", "enter", "q":
+			return m, func() tea.Msg { return common.ExitFormMsg{} }
+		case "v", " ":
+			m.Revealed = !m.Revealed
+		case "c", "y":
+			if clipboard.Unsupported {
+				m.status = "no clipboard available"
+			} else if err := clipboard.WriteAll(m.Secret); err != nil {
+				m.status = fmt.Sprintf("copy failed: %v", err)
+			} else {
+				m.status = "copied to clipboard"
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m SecretView) View() string {
+	containerStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#9cca69")).
+		Foreground(lipgloss.Color("#a7abca")).
+		Align(lipgloss.Center).
+		Padding(0, 1).
+		Width(50)
+
+	secretStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#cda162")).
+		Padding(0, 1).
+		Width(44)
+
+	secret := strings.Repeat("•", len([]rune(m.Secret)))
+	toggle := "v: reveal"
+	if m.Revealed {
+		secret = m.Secret
+		toggle = "v: hide"
+	}
+
+	return containerStyle.Render(
+		lipgloss.JoinVertical(lipgloss.Center,
+			fmt.Sprintf("Saved %s for '%s'", m.Label, m.SSID),
+			secretStyle.Render(secret),
+			m.status,
+			fmt.Sprintf("%s  c: copy  esc: close", toggle),
+		),
+	)
+}
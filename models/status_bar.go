@@ -13,9 +13,28 @@ import (
 )
 
 type keyMap struct {
-	Scan   key.Binding
-	Select key.Binding
-	Quit   key.Binding
+	Scan     key.Binding
+	Select   key.Binding
+	Quit     key.Binding
+	Hotspot  key.Binding
+	ShareQR  key.Binding
+	Secret   key.Binding
+	Import   key.Binding
+	Export   key.Binding
+	EditWG   key.Binding
+	Rename   key.Binding
+	AutoVPN  key.Binding
+	Delete   key.Binding
+	WifiVPN  key.Binding
+	APs      key.Binding
+	Options  key.Binding
+	Channels key.Binding
+	Check    key.Binding
+	Checks   key.Binding
+	Portal   key.Binding
+	Airplane key.Binding
+	Undo     key.Binding
+	History  key.Binding
 }
 
 func (k keyMap) ShortHelp() []key.Binding {
@@ -27,6 +46,13 @@ func (k keyMap) ShortHelp() []key.Binding {
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Scan, k.Select, k.Quit}, // first column
+		{k.Hotspot, k.ShareQR, k.Secret},
+		{k.Import, k.Export, k.EditWG},
+		{k.Rename, k.AutoVPN, k.Delete},
+		{k.WifiVPN, k.APs, k.Options},
+		{k.Channels, k.Check, k.Checks},
+		{k.Portal, k.Airplane, k.Undo},
+		{k.History},
 	}
 }
 
@@ -43,11 +69,89 @@ var keys = keyMap{
 		key.WithKeys("q", "esc", "ctrl+c"),
 		key.WithHelp("ctrl+q/esc:", "quit"),
 	),
+	Hotspot: key.NewBinding(
+		key.WithKeys("h"),
+		key.WithHelp("h:", "hotspot"),
+	),
+	ShareQR: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s:", "share as qr code"),
+	),
+	Secret: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p:", "show saved password"),
+	),
+	Import: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i:", "import profile"),
+	),
+	Export: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e:", "export profile"),
+	),
+	EditWG: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w:", "edit wireguard"),
+	),
+	Rename: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n:", "rename vpn"),
+	),
+	AutoVPN: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a:", "toggle vpn autoconnect"),
+	),
+	Delete: key.NewBinding(
+		key.WithKeys("delete"),
+		key.WithHelp("del:", "delete profile"),
+	),
+	WifiVPN: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v:", "vpns for this wi-fi"),
+	),
+	APs: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b:", "access points"),
+	),
+	Options: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o:", "wi-fi settings"),
+	),
+	Channels: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c:", "channel congestion"),
+	),
+	Check: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x:", "check connectivity"),
+	),
+	Checks: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t:", "toggle connectivity checks"),
+	),
+	Portal: key.NewBinding(
+		key.WithKeys("l"),
+		key.WithHelp("l:", "open captive portal"),
+	),
+	Airplane: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m:", "airplane mode"),
+	),
+	Undo: key.NewBinding(
+		key.WithKeys("u", "ctrl+z"),
+		key.WithHelp("u:", "undo delete"),
+	),
+	History: key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H:", "connection history"),
+	),
 }
 
 type StatusBarData struct {
-	Input textinput.Model
-	Err   error
+	Input   textinput.Model
+	Err     error
+	Notice  string
+	Warning string // shown next to the input while it is focused
 }
 
 func ModelStatusBar() StatusBarData {
@@ -101,6 +205,17 @@ func (m StatusBarData) View() string {
 		}
 	}
 
+	left := m.Input.View()
+	if m.Warning != "" && m.Input.Focused() {
+		warning := lipgloss.NewStyle().Foreground(lipgloss.Color("#e06c75")).Render(m.Warning)
+		left += "  " + warning
+		inputLen += 2 + lipgloss.Width(warning)
+	}
+	if m.Notice != "" && !m.Input.Focused() {
+		left = lipgloss.NewStyle().Foreground(lipgloss.Color("#9cca69")).Render(m.Notice)
+		inputLen = lipgloss.Width(left)
+	}
+
 	keyIndex := keyHelp.View(keys)
 
 	ansi := regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
@@ -109,5 +224,5 @@ func (m StatusBarData) View() string {
 	totalWidth := common.WindowDimensions().Width
 	remainingWidth := totalWidth - (inputLen + len(clean)) - 6 // extra 6 to account for automatic padding
 
-	return m.Input.View() + strings.Repeat(" ", max(remainingWidth, 0)) + keyIndex
+	return left + strings.Repeat(" ", max(remainingWidth, 0)) + keyIndex
 }
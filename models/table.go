@@ -17,6 +17,8 @@ type TableData struct {
 	vpnData         []common.VpnConnection
 	knownNetworks   []common.KnownNetwork
 	scannedNetworks []common.ScannedNetwork
+	history         common.SignalHistory
+	throughput      common.Throughput
 }
 
 func TableModel(
@@ -61,13 +63,13 @@ func (m TableData) View() string {
 	if m.deviceData != nil {
 		tableData = common.FormatDeviceData(m.deviceData)
 	} else if m.stationData != nil {
-		tableData = common.FormatStationData(m.stationData)
+		tableData = common.FormatStationData(m.stationData, m.throughput)
 	} else if m.vpnData != nil {
 		tableData = common.FormatVpnData(m.vpnData)
 	} else if m.knownNetworks != nil {
-		tableData = common.FormatKnownNetworksData(m.knownNetworks, m.selectedRow, m.height)
+		tableData = common.FormatKnownNetworksData(m.knownNetworks, m.history, m.selectedRow, m.height)
 	} else {
-		tableData = common.FormatScannedNetworksData(m.scannedNetworks, m.selectedRow, m.height)
+		tableData = common.FormatScannedNetworksData(m.scannedNetworks, m.history, m.selectedRow, m.height)
 	}
 
 	table := table.New().
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // TablesModel is a container model that holds all the main tables.
@@ -17,6 +18,9 @@ type TablesModel struct {
 	VpnData         []common.VpnConnection
 	KnownNetworks   []common.KnownNetwork
 	ScannedNetworks []common.ScannedNetwork
+	SignalHistory   common.SignalHistory
+	Throughput      common.Throughput
+	Connectivity    common.ConnectivityStatus
 }
 
 func (m TablesModel) Init() tea.Cmd {
@@ -28,6 +32,38 @@ func (m TablesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// header sums up NetworkManager's state and whether we're really online.
+func (m TablesModel) header() string {
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#a7abca"))
+	color := map[uint32]string{
+		common.ConnectivityFull:    "#9cca69",
+		common.ConnectivityLimited: "#cda162",
+		common.ConnectivityPortal:  "#cda162",
+		common.ConnectivityNone:    "#e06c75",
+	}[m.Connectivity.Connectivity]
+	if color == "" {
+		color = "#a7abca"
+	}
+	valueStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(color))
+
+	internet := common.ConnectivityString(m.Connectivity.Connectivity)
+	checks := "on"
+	if !m.Connectivity.CheckAvailable {
+		checks = "unavailable"
+	} else if !m.Connectivity.CheckEnabled {
+		checks = "off"
+	}
+	line := labelStyle.Render(" NetworkManager: ") + valueStyle.Render(common.NMStateString(m.Connectivity.State)) +
+		labelStyle.Render(" • Internet: ") + valueStyle.Render(internet) +
+		labelStyle.Render(" • Checks: "+checks)
+	if m.Connectivity.Connectivity == common.ConnectivityPortal && m.Connectivity.CheckURI != "" {
+		// The link is added after styling so lipgloss doesn't split the escape
+		line += labelStyle.Render(" • Sign in at ") + common.Hyperlink(m.Connectivity.CheckURI, m.Connectivity.CheckURI) +
+			labelStyle.Render(" (l to open)")
+	}
+	return line + "\n"
+}
+
 // View renders all tables in order.
 func (m TablesModel) View() string {
 	deviceTable := TableModel("Device", m.SelectedBox == 0, m.SelectedEntry, -1, m.DeviceData, nil, nil, nil, nil)
@@ -35,14 +71,33 @@ func (m TablesModel) View() string {
 	vpnTableModel := TableModel("Virtual Private Networks", m.SelectedBox == 2, m.SelectedEntry, -1, nil, nil, m.VpnData, nil, nil)
 	knownNetsTable := TableModel("Known Networks", m.SelectedBox == 3, m.SelectedEntry, m.NetsHeight, nil, nil, nil, m.KnownNetworks, nil)
 	scannedNetsTable := TableModel("New Networks", m.SelectedBox == 4, m.SelectedEntry, m.NetsHeight, nil, nil, nil, nil, m.ScannedNetworks)
+	stationTable.throughput = m.Throughput
+	knownNetsTable.history = m.SignalHistory
+	scannedNetsTable.history = m.SignalHistory
+
+	deviceView := deviceTable.View()
+	if len(m.DeviceData) > 0 && m.DeviceData[0].HardBlocked {
+		deviceView += lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#e06c75")).
+			MaxWidth(common.WindowDimensions().Width).
+			Render(" "+common.HardBlockHint) + "\n"
+	}
 
 	vpnView := vpnTableModel.View()
 	if len(m.VpnData) == 0 {
 		vpnView = ""
+	} else if m.SelectedBox == 2 && m.SelectedEntry < len(m.VpnData) && m.VpnData[m.SelectedEntry].Banner != "" {
+		// Show the server's login banner for the selected VPN
+		banner := strings.Join(strings.Fields(m.VpnData[m.SelectedEntry].Banner), " ")
+		vpnView += lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#cda162")).
+			MaxWidth(common.WindowDimensions().Width).
+			Render(" Banner: "+banner) + "\n"
 	}
 
 	return strings.Join([]string{
-		deviceTable.View(),
+		m.header(),
+		deviceView,
 		stationTable.View(),
 		vpnView,
 		knownNetsTable.View(),
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"fmt"
+	"netpala/common"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// VpnPicker chooses the VPNs a known network brings up as secondaries.
+type VpnPicker struct {
+	SSID     string
+	vpns     []common.VpnConnection
+	selected map[string]bool
+	others   []string // secondaries that aren't listed VPNs, kept as they are
+	cursor   int
+}
+
+func ModelVpnPicker(ssid string, vpns []common.VpnConnection, secondaries []string) VpnPicker {
+	listed := make(map[string]bool)
+	for _, vpn := range vpns {
+		listed[vpn.UUID] = true
+	}
+	selected := make(map[string]bool)
+	var others []string
+	for _, uuid := range secondaries {
+		selected[uuid] = true
+		if !listed[uuid] {
+			others = append(others, uuid)
+		}
+	}
+	return VpnPicker{SSID: ssid, vpns: vpns, selected: selected, others: others}
+}
+
+func (m VpnPicker) Init() tea.Cmd {
+	return nil
+}
+
+func (m VpnPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch key := msg.(type) {
+	case tea.KeyMsg:
+		switch key.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.vpns)-1 {
+				m.cursor++
+			}
+		case " ", "x":
+			if len(m.vpns) > 0 {
+				uuid := m.vpns[m.cursor].UUID
+				m.selected[uuid] = !m.selected[uuid]
+			}
+		case "enter":
+			// Keep the order of the VPN table so the result is stable.
+			uuids := []string{}
+			for _, vpn := range m.vpns {
+				if m.selected[vpn.UUID] {
+					uuids = append(uuids, vpn.UUID)
+				}
+			}
+			uuids = append(uuids, m.others...)
+			return m, func() tea.Msg { return common.SubmitSecondariesMsg{UUIDs: uuids} }
+		case "esc", "ctrl+c", "q":
+			return m, func() tea.Msg { return common.ExitFormMsg{} }
+		}
+	}
+	return m, nil
+}
+
+func (m VpnPicker) View() string {
+	containerStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#9cca69")).
+		Foreground(lipgloss.Color("#a7abca")).
+		Padding(0, 1)
+
+	activeStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#cda162"))
+
+	lines := []string{
+		fmt.Sprintf("Start these VPNs whenever '%s' connects:", m.SSID),
+		"",
+	}
+	if len(m.vpns) == 0 {
+		lines = append(lines, "No VPN profiles saved yet (press 'i' to import one)")
+	}
+	for i, vpn := range m.vpns {
+		box := "[ ]"
+		if m.selected[vpn.UUID] {
+			box = "[x]"
+		}
+		line := fmt.Sprintf("%s %s (%s)", box, vpn.Name, vpn.ConnType)
+		if i == m.cursor {
+			line = activeStyle.Render("» " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	if len(m.others) > 0 {
+		lines = append(lines, "", fmt.Sprintf("%d other secondary connection(s) are kept", len(m.others)))
+	}
+	lines = append(lines, "", "space: toggle • enter: save • esc: cancel")
+
+	return containerStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
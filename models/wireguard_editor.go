@@ -0,0 +1,378 @@
+package models
+
+import (
+	"fmt"
+	"netpala/common"
+	"netpala/network"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Focus positions in the editor. The peer fields are skipped while the
+// profile has no peers.
+const (
+	wgFocusPrivateKey = iota
+	wgFocusListenPort
+	wgFocusFwMark
+	wgFocusMTU
+	wgFocusPublicKey
+	wgFocusPresharedKey
+	wgFocusEndpoint
+	wgFocusAllowedIPs
+	wgFocusKeepalive
+	wgFocusSave
+	wgFocusCount
+)
+
+type WireGuardEditor struct {
+	Name string
+
+	inputs  []textinput.Model // one per focus position before wgFocusSave
+	peers   []common.WireGuardPeer
+	peer    int
+	focused int
+	err     error // why the last save was refused
+}
+
+// newFieldInput builds a bordered editor input, shared with the Wi-Fi
+// settings editor. Numeric inputs only accept unsigned numbers.
+func newFieldInput(placeholder string, limit int, numeric bool) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	input.Prompt = ""
+	input.Width = 44
+	input.CharLimit = limit
+	if numeric {
+		input.Width = 10
+		input.Validate = func(s string) error {
+			if s == "" {
+				return nil
+			}
+			_, err := strconv.ParseUint(s, 10, 32)
+			return err
+		}
+	}
+	return input
+}
+
+func ModelWireGuardEditor(name string, cfg common.WireGuardConfig) WireGuardEditor {
+	inputs := make([]textinput.Model, wgFocusSave)
+	inputs[wgFocusPrivateKey] = newFieldInput("base64 key (ctrl+g to generate)", 44, false)
+	inputs[wgFocusListenPort] = newFieldInput("random", 5, true)
+	inputs[wgFocusFwMark] = newFieldInput("off", 10, true)
+	inputs[wgFocusMTU] = newFieldInput("auto", 5, true)
+	inputs[wgFocusPublicKey] = newFieldInput("base64 key", 44, false)
+	inputs[wgFocusPresharedKey] = newFieldInput("none", 44, false)
+	inputs[wgFocusEndpoint] = newFieldInput("host:port", 255, false)
+	inputs[wgFocusAllowedIPs] = newFieldInput("0.0.0.0/0, ::/0", 1024, false)
+	inputs[wgFocusKeepalive] = newFieldInput("off", 5, true)
+
+	inputs[wgFocusPrivateKey].SetValue(cfg.PrivateKey)
+	inputs[wgFocusListenPort].SetValue(formatNumber(cfg.ListenPort))
+	inputs[wgFocusFwMark].SetValue(formatNumber(cfg.FwMark))
+	inputs[wgFocusMTU].SetValue(formatNumber(cfg.MTU))
+	inputs[wgFocusPrivateKey].Focus()
+
+	m := WireGuardEditor{
+		Name:   name,
+		inputs: inputs,
+		peers:  append([]common.WireGuardPeer(nil), cfg.Peers...),
+	}
+	m.loadPeer()
+	return m
+}
+
+func formatNumber(n uint32) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(n), 10)
+}
+
+// parseNumber reads a decimal input, where empty means 0. Range checks are
+// left to validation so it can say which field is wrong.
+func parseNumber(field, s string) (uint32, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%s '%s' is not a valid number", field, s)
+	}
+	return uint32(n), nil
+}
+
+// loadPeer fills the peer inputs from the selected peer.
+func (m *WireGuardEditor) loadPeer() {
+	p := common.WireGuardPeer{}
+	if m.peer < len(m.peers) {
+		p = m.peers[m.peer]
+	}
+	m.inputs[wgFocusPublicKey].SetValue(p.PublicKey)
+	m.inputs[wgFocusPresharedKey].SetValue(p.PresharedKey)
+	m.inputs[wgFocusEndpoint].SetValue(p.Endpoint)
+	m.inputs[wgFocusAllowedIPs].SetValue(strings.Join(p.AllowedIPs, ", "))
+	m.inputs[wgFocusKeepalive].SetValue(formatNumber(p.Keepalive))
+}
+
+// storePeer writes the peer inputs back into the selected peer.
+func (m *WireGuardEditor) storePeer() error {
+	if m.peer >= len(m.peers) {
+		return nil
+	}
+	keepalive, err := parseNumber("keepalive", m.inputs[wgFocusKeepalive].Value())
+	if err != nil {
+		return err
+	}
+	var allowed []string
+	for _, ip := range strings.Split(m.inputs[wgFocusAllowedIPs].Value(), ",") {
+		if ip = strings.TrimSpace(ip); ip != "" {
+			allowed = append(allowed, ip)
+		}
+	}
+	m.peers[m.peer] = common.WireGuardPeer{
+		PublicKey:    strings.TrimSpace(m.inputs[wgFocusPublicKey].Value()),
+		PresharedKey: strings.TrimSpace(m.inputs[wgFocusPresharedKey].Value()),
+		Endpoint:     strings.TrimSpace(m.inputs[wgFocusEndpoint].Value()),
+		AllowedIPs:   allowed,
+		Keepalive:    keepalive,
+	}
+	return nil
+}
+
+func (m *WireGuardEditor) selectPeer(idx int) {
+	// Stay on the peer until its inputs can be stored
+	if m.err = m.storePeer(); m.err != nil {
+		return
+	}
+	m.peer = idx
+	m.loadPeer()
+}
+
+func (m *WireGuardEditor) setFocus(idx int) {
+	m.focused = idx
+	for i := range m.inputs {
+		if i == idx {
+			m.inputs[i].Focus()
+		} else {
+			m.inputs[i].Blur()
+		}
+	}
+}
+
+func (m *WireGuardEditor) moveFocus(step int) {
+	next := m.focused
+	for {
+		next = (next + step + wgFocusCount) % wgFocusCount
+		if len(m.peers) > 0 || next < wgFocusPublicKey || next > wgFocusKeepalive {
+			break
+		}
+	}
+	m.setFocus(next)
+}
+
+func (m WireGuardEditor) config() (common.WireGuardConfig, error) {
+	m.peers = append([]common.WireGuardPeer(nil), m.peers...)
+	if err := m.storePeer(); err != nil {
+		return common.WireGuardConfig{}, err
+	}
+	cfg := common.WireGuardConfig{
+		PrivateKey: strings.TrimSpace(m.inputs[wgFocusPrivateKey].Value()),
+		Peers:      m.peers,
+	}
+	var err error
+	if cfg.ListenPort, err = parseNumber("listen port", m.inputs[wgFocusListenPort].Value()); err != nil {
+		return cfg, err
+	}
+	if cfg.FwMark, err = parseNumber("fwmark", m.inputs[wgFocusFwMark].Value()); err != nil {
+		return cfg, err
+	}
+	if cfg.MTU, err = parseNumber("MTU", m.inputs[wgFocusMTU].Value()); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+func (m WireGuardEditor) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m WireGuardEditor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "tab", "down":
+			m.moveFocus(1)
+			return m, nil
+		case "shift+tab", "up":
+			m.moveFocus(-1)
+			return m, nil
+		case "ctrl+g":
+			key, err := common.GenerateWireGuardKey()
+			if err != nil {
+				return m, func() tea.Msg { return common.ErrMsg{Err: err} }
+			}
+			m.inputs[wgFocusPrivateKey].SetValue(key)
+			return m, nil
+		case "ctrl+n":
+			if m.err = m.storePeer(); m.err != nil {
+				return m, nil
+			}
+			m.peers = append(m.peers, common.WireGuardPeer{})
+			m.peer = len(m.peers) - 1
+			m.loadPeer()
+			m.setFocus(wgFocusPublicKey)
+			return m, nil
+		case "ctrl+d":
+			if len(m.peers) == 0 {
+				return m, nil
+			}
+			m.peers = append(m.peers[:m.peer], m.peers[m.peer+1:]...)
+			if m.peer >= len(m.peers) && m.peer > 0 {
+				m.peer--
+			}
+			m.loadPeer()
+			if len(m.peers) == 0 && m.focused >= wgFocusPublicKey && m.focused <= wgFocusKeepalive {
+				m.setFocus(wgFocusSave)
+			}
+			return m, nil
+		case "ctrl+left", "ctrl+p":
+			if m.peer > 0 {
+				m.selectPeer(m.peer - 1)
+			}
+			return m, nil
+		case "ctrl+right", "ctrl+o":
+			if m.peer < len(m.peers)-1 {
+				m.selectPeer(m.peer + 1)
+			}
+			return m, nil
+		case "enter":
+			if m.focused == wgFocusSave {
+				cfg, err := m.config()
+				if err == nil {
+					err = network.ValidateWireGuardConfig(cfg)
+				}
+				// Keep the editor open on bad input so nothing typed is lost
+				if m.err = err; m.err != nil {
+					return m, nil
+				}
+				return m, func() tea.Msg { return common.SubmitWireGuardMsg{Config: cfg} }
+			}
+			m.moveFocus(1)
+			return m, nil
+		case "esc", "ctrl+c":
+			return m, func() tea.Msg { return common.ExitFormMsg{} }
+		}
+	}
+
+	if m.focused < wgFocusSave {
+		var cmd tea.Cmd
+		m.inputs[m.focused], cmd = m.inputs[m.focused].Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m WireGuardEditor) View() string {
+	inactiveBorderStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#444a66")).
+		Padding(0, 1)
+
+	activeBorderStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#a7abca")).
+		Padding(0, 1)
+
+	inactiveLabelStyle := lipgloss.NewStyle().
+		Bold(false).
+		Foreground(lipgloss.Color("#a7abca"))
+
+	activeLabelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#cda162"))
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#444a66"))
+
+	warningStyle := lipgloss.NewStyle().
+		Width(50).
+		Foreground(lipgloss.Color("#e06c75"))
+
+	formStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#9cca69")).
+		Padding(0, 1)
+
+	field := func(idx int, text string) string {
+		label := inactiveLabelStyle.Render(text)
+		box := inactiveBorderStyle.Render(m.inputs[idx].View())
+		if m.focused == idx {
+			label = activeLabelStyle.Render(text)
+			box = activeBorderStyle.Render(m.inputs[idx].View())
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, label, box)
+	}
+	row := func(fields ...string) string {
+		return lipgloss.JoinHorizontal(lipgloss.Top, fields...)
+	}
+
+	publicKey := "-"
+	if priv := strings.TrimSpace(m.inputs[wgFocusPrivateKey].Value()); priv != "" {
+		if pub, err := common.WireGuardPublicKey(priv); err == nil {
+			publicKey = pub
+		} else {
+			publicKey = "invalid private key"
+		}
+	}
+
+	sections := []string{
+		inactiveLabelStyle.Render(fmt.Sprintf("WireGuard: %s", m.Name)),
+		field(wgFocusPrivateKey, "\nPrivate key:"),
+		inactiveLabelStyle.Render("Public key: ") + activeLabelStyle.Render(publicKey),
+		row(field(wgFocusListenPort, "\nListen port:"), " ",
+			field(wgFocusFwMark, "\nFwMark:"), " ",
+			field(wgFocusMTU, "\nMTU:")),
+	}
+
+	if len(m.peers) == 0 {
+		sections = append(sections, inactiveLabelStyle.Render("\nNo peers (ctrl+n to add one)"))
+	} else {
+		sections = append(sections,
+			inactiveLabelStyle.Render(fmt.Sprintf("\nPeer %d of %d", m.peer+1, len(m.peers))),
+			field(wgFocusPublicKey, "Public key:"),
+			field(wgFocusPresharedKey, "Preshared key:"),
+			field(wgFocusEndpoint, "Endpoint:"),
+			field(wgFocusAllowedIPs, "Allowed IPs:"),
+			field(wgFocusKeepalive, "Persistent keepalive (s):"),
+		)
+	}
+
+	buttonText := "Save"
+	submitLabel := inactiveBorderStyle.
+		Width(48).
+		Align(lipgloss.Center).
+		Render(buttonText)
+	if m.focused == wgFocusSave {
+		submitLabel = activeBorderStyle.
+			Width(48).
+			Bold(true).
+			Align(lipgloss.Center).
+			BorderForeground(lipgloss.Color("#cda162")).
+			Render(buttonText)
+	}
+	sections = append(sections,
+		submitLabel,
+		hintStyle.Render("ctrl+n add peer • ctrl+d remove peer • ctrl+p/ctrl+o prev/next peer"),
+	)
+	if m.err != nil {
+		sections = append(sections, warningStyle.Render(m.err.Error()))
+	}
+
+	return formStyle.Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
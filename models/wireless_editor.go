@@ -0,0 +1,359 @@
+package models
+
+import (
+	"fmt"
+	"netpala/common"
+	"netpala/network"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Focus positions in the editor. The MAC address input is skipped unless
+// an explicit address is chosen.
+const (
+	wlFocusBand = iota
+	wlFocusChannel
+	wlFocusPowerSave
+	wlFocusMTU
+	wlFocusMACMode
+	wlFocusMAC
+	wlFocusWakeOnWlan
+	wlFocusPMF
+	wlFocusSave
+	wlFocusCount
+)
+
+type wlOption struct {
+	Label string
+	Value string
+}
+
+var (
+	wlBands = []wlOption{{"auto", ""}, {"2.4 GHz", "bg"}, {"5 GHz", "a"}}
+	// Values are NM_SETTING_WIRELESS_POWERSAVE_*
+	wlPowerSave = []wlOption{{"default", "0"}, {"ignore", "1"}, {"disable", "2"}, {"enable", "3"}}
+	wlMACModes  = []wlOption{
+		{"default", ""},
+		{"permanent", "permanent"},
+		{"random", "random"},
+		{"stable", "stable"},
+		{"preserve", "preserve"},
+		{"explicit", "explicit"},
+	}
+	// Values are NM_SETTING_WIRELESS_WAKE_ON_WLAN_* flags
+	wlWakeOnWlan = []wlOption{
+		{"default", "1"},
+		{"ignore", "32768"},
+		{"disabled", "0"},
+		{"magic packet", "8"},
+		{"disconnect", "4"},
+		{"any", "2"},
+	}
+	// Values are NM_SETTING_WIRELESS_SECURITY_PMF_*
+	wlPMF = []wlOption{{"default", "0"}, {"disable", "1"}, {"optional", "2"}, {"required", "3"}}
+)
+
+type WirelessEditor struct {
+	Name        string
+	Address     string // current hardware address of the device
+	PermAddress string // burned-in hardware address
+
+	channel   textinput.Model
+	mtu       textinput.Model
+	mac       textinput.Model
+	choices   map[int]int // focus position -> index into its option list
+	secured   bool
+	extraWoWL uint32 // wake-on-wlan flags that match no option, kept as they are
+	focused   int
+	err       error // why the last save was refused
+}
+
+func (m WirelessEditor) options(field int) []wlOption {
+	switch field {
+	case wlFocusBand:
+		return wlBands
+	case wlFocusPowerSave:
+		return wlPowerSave
+	case wlFocusMACMode:
+		return wlMACModes
+	case wlFocusWakeOnWlan:
+		return wlWakeOnWlan
+	case wlFocusPMF:
+		return wlPMF
+	}
+	return nil
+}
+
+func optionIndex(options []wlOption, value string) (int, bool) {
+	for i, o := range options {
+		if o.Value == value {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func ModelWirelessEditor(name string, cfg common.WirelessConfig, address, permAddress string) WirelessEditor {
+	m := WirelessEditor{
+		Name:        name,
+		Address:     address,
+		PermAddress: permAddress,
+		channel:     newFieldInput("auto", 3, true),
+		mtu:         newFieldInput("auto", 5, true),
+		mac:         newFieldInput("AA:BB:CC:DD:EE:FF", 17, false),
+		choices:     map[int]int{},
+		secured:     cfg.Secured,
+	}
+	m.mac.Width = 20
+
+	m.channel.SetValue(formatNumber(cfg.Channel))
+	m.mtu.SetValue(formatNumber(cfg.MTU))
+	m.choices[wlFocusBand], _ = optionIndex(wlBands, cfg.Band)
+	m.choices[wlFocusPowerSave], _ = optionIndex(wlPowerSave, strconv.FormatUint(uint64(cfg.PowerSave), 10))
+	m.choices[wlFocusPMF], _ = optionIndex(wlPMF, strconv.FormatInt(int64(cfg.PMF), 10))
+
+	wol, ok := optionIndex(wlWakeOnWlan, strconv.FormatUint(uint64(cfg.WakeOnWlan), 10))
+	if !ok {
+		// A combination set elsewhere; leave it alone unless changed here
+		m.extraWoWL = cfg.WakeOnWlan
+	}
+	m.choices[wlFocusWakeOnWlan] = wol
+
+	mode, ok := optionIndex(wlMACModes, cfg.ClonedMAC)
+	if !ok {
+		mode, _ = optionIndex(wlMACModes, "explicit")
+		m.mac.SetValue(strings.ToUpper(cfg.ClonedMAC))
+	}
+	m.choices[wlFocusMACMode] = mode
+	return m
+}
+
+func (m WirelessEditor) choice(field int) string {
+	return m.options(field)[m.choices[field]].Value
+}
+
+func (m WirelessEditor) visible(field int) bool {
+	switch field {
+	case wlFocusMAC:
+		return m.choice(wlFocusMACMode) == "explicit"
+	case wlFocusPMF:
+		return m.secured
+	}
+	return true
+}
+
+func (m *WirelessEditor) setFocus(field int) {
+	m.focused = field
+	for idx, input := range map[int]*textinput.Model{wlFocusChannel: &m.channel, wlFocusMTU: &m.mtu, wlFocusMAC: &m.mac} {
+		if idx == field {
+			input.Focus()
+		} else {
+			input.Blur()
+		}
+	}
+}
+
+func (m *WirelessEditor) moveFocus(step int) {
+	next := m.focused
+	for {
+		next = (next + step + wlFocusCount) % wlFocusCount
+		if m.visible(next) {
+			break
+		}
+	}
+	m.setFocus(next)
+}
+
+func (m WirelessEditor) config() (common.WirelessConfig, error) {
+	parse := func(s string) uint32 {
+		n, _ := strconv.ParseUint(s, 10, 32)
+		return uint32(n)
+	}
+	cfg := common.WirelessConfig{
+		Band:       m.choice(wlFocusBand),
+		PowerSave:  parse(m.choice(wlFocusPowerSave)),
+		ClonedMAC:  m.choice(wlFocusMACMode),
+		WakeOnWlan: parse(m.choice(wlFocusWakeOnWlan)),
+		PMF:        int32(parse(m.choice(wlFocusPMF))),
+		Secured:    m.secured,
+	}
+	if m.extraWoWL != 0 {
+		cfg.WakeOnWlan = m.extraWoWL
+	}
+	if cfg.ClonedMAC == "explicit" {
+		cfg.ClonedMAC = strings.TrimSpace(m.mac.Value())
+	}
+	var err error
+	if cfg.Channel, err = parseNumber("channel", m.channel.Value()); err != nil {
+		return cfg, err
+	}
+	if cfg.MTU, err = parseNumber("MTU", m.mtu.Value()); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+func (m WirelessEditor) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m WirelessEditor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "tab", "down":
+			m.moveFocus(1)
+			return m, nil
+		case "shift+tab", "up":
+			m.moveFocus(-1)
+			return m, nil
+		case "left", "right", " ":
+			if options := m.options(m.focused); options != nil {
+				step := 1
+				if msg.String() == "left" {
+					step = len(options) - 1
+				}
+				m.choices[m.focused] = (m.choices[m.focused] + step) % len(options)
+				if m.focused == wlFocusWakeOnWlan {
+					m.extraWoWL = 0
+				}
+				return m, nil
+			}
+		case "enter":
+			if m.focused == wlFocusSave {
+				cfg, err := m.config()
+				if err == nil {
+					err = network.ValidateWirelessConfig(cfg)
+				}
+				// Keep the editor open on bad input so nothing typed is lost
+				if m.err = err; m.err != nil {
+					return m, nil
+				}
+				return m, func() tea.Msg { return common.SubmitWirelessMsg{Config: cfg} }
+			}
+			m.moveFocus(1)
+			return m, nil
+		case "esc", "ctrl+c":
+			return m, func() tea.Msg { return common.ExitFormMsg{} }
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.focused {
+	case wlFocusChannel:
+		m.channel, cmd = m.channel.Update(msg)
+	case wlFocusMTU:
+		m.mtu, cmd = m.mtu.Update(msg)
+	case wlFocusMAC:
+		m.mac, cmd = m.mac.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m WirelessEditor) View() string {
+	inactiveBorderStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#444a66")).
+		Padding(0, 1)
+
+	activeBorderStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#a7abca")).
+		Padding(0, 1)
+
+	inactiveLabelStyle := lipgloss.NewStyle().
+		Bold(false).
+		Foreground(lipgloss.Color("#a7abca"))
+
+	activeLabelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#cda162"))
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#444a66"))
+
+	warningStyle := lipgloss.NewStyle().
+		Width(42).
+		Foreground(lipgloss.Color("#e06c75"))
+
+	formStyle := lipgloss.NewStyle().
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#9cca69")).
+		Padding(0, 1)
+
+	label := func(field int, text string) string {
+		if m.focused == field {
+			return activeLabelStyle.Render(text)
+		}
+		return inactiveLabelStyle.Render(text)
+	}
+	field := func(idx int, text string, input textinput.Model) string {
+		box := inactiveBorderStyle.Render(input.View())
+		if m.focused == idx {
+			box = activeBorderStyle.Render(input.View())
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, label(idx, text), box)
+	}
+	choice := func(idx int, text string) string {
+		value := m.options(idx)[m.choices[idx]].Label
+		if idx == wlFocusWakeOnWlan && m.extraWoWL != 0 {
+			value = fmt.Sprintf("custom (0x%x)", m.extraWoWL)
+		}
+		return label(idx, fmt.Sprintf("%-16s ‹ %s ›", text, value))
+	}
+
+	permanent := m.PermAddress
+	if permanent == "" {
+		permanent = "-"
+	}
+	address := m.Address
+	if !strings.EqualFold(m.Address, m.PermAddress) && m.PermAddress != "" {
+		address += " (cloned)"
+	}
+
+	sections := []string{
+		inactiveLabelStyle.Render(fmt.Sprintf("Wi-Fi settings: %s", m.Name)),
+		inactiveLabelStyle.Render("Current address:   ") + activeLabelStyle.Render(address),
+		inactiveLabelStyle.Render("Permanent address: ") + activeLabelStyle.Render(permanent),
+		"",
+		choice(wlFocusBand, "Band:"),
+		field(wlFocusChannel, "Channel:", m.channel),
+		choice(wlFocusPowerSave, "Power saving:"),
+		field(wlFocusMTU, "MTU:", m.mtu),
+		choice(wlFocusMACMode, "MAC address:"),
+	}
+	if m.visible(wlFocusMAC) {
+		sections = append(sections, field(wlFocusMAC, "Cloned address:", m.mac))
+	}
+	sections = append(sections, choice(wlFocusWakeOnWlan, "Wake on WLAN:"))
+	if m.visible(wlFocusPMF) {
+		sections = append(sections, choice(wlFocusPMF, "PMF (802.11w):"))
+	}
+
+	submitLabel := inactiveBorderStyle.
+		Width(40).
+		Align(lipgloss.Center).
+		Render("Save")
+	if m.focused == wlFocusSave {
+		submitLabel = activeBorderStyle.
+			Width(40).
+			Bold(true).
+			Align(lipgloss.Center).
+			BorderForeground(lipgloss.Color("#cda162")).
+			Render("Save")
+	}
+	sections = append(sections,
+		"",
+		submitLabel,
+		hintStyle.Render("←/→ change • tab next field • esc cancel"),
+	)
+	if m.err != nil {
+		sections = append(sections, warningStyle.Render(m.err.Error()))
+	}
+
+	return formStyle.Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
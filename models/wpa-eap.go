@@ -16,8 +16,19 @@ type WpaEapForm struct {
 	Phase2Auth     	selector.Model
 	Identity       	textinput.Model
 	Password       	textinput.Model
+	AnonIdentity   	textinput.Model
 	CaCert         	textinput.Model
+	DomainMatch    	textinput.Model
+	AltSubject     	textinput.Model
+	ClientCert     	textinput.Model
+	PrivateKey     	textinput.Model
+	KeyPassword    	textinput.Model
+	SystemCA       	bool
+	PeapVersion    	int	// index into peapVersions
+	PeapLabel      	bool
 	focused        	int
+	err            	error
+	preset         	string	// name of the preset that filled the form, if any
 	
 	SSIDSelected		string
 	EapSelected   	bool
@@ -29,6 +40,32 @@ type EAPMethod struct {
 	Type string
 }
 
+// Focus positions, in display order.
+const (
+	eapFieldMethod = iota
+	eapFieldPhase2
+	eapFieldIdentity
+	eapFieldAnonIdentity
+	eapFieldPassword
+	eapFieldSystemCA
+	eapFieldCaCert
+	eapFieldDomainMatch
+	eapFieldAltSubject
+	eapFieldPeapVersion
+	eapFieldPeapLabel
+	eapFieldClientCert
+	eapFieldPrivateKey
+	eapFieldKeyPassword
+	eapFieldSubmit
+	eapFieldCount
+)
+
+var peapVersions = []struct{ Value, Label string }{
+	{"", "auto"},
+	{"0", "v0"},
+	{"1", "v1"},
+}
+
 func ModelWpaEapForm() WpaEapForm {
 	Identity := textinput.New()
 	Identity.Placeholder = "Identity"
@@ -44,12 +81,50 @@ func ModelWpaEapForm() WpaEapForm {
 	Password.EchoMode = textinput.EchoPassword
 	Password.EchoCharacter = '*'
 
+	AnonIdentity := textinput.New()
+	AnonIdentity.Placeholder = "e.g. anonymous@example.edu"
+	AnonIdentity.Prompt = ""
+	AnonIdentity.Width = 32
+	AnonIdentity.CharLimit = 256
+
+	DomainMatch := textinput.New()
+	DomainMatch.Placeholder = "e.g. radius.example.edu"
+	DomainMatch.Prompt = ""
+	DomainMatch.Width = 32
+	DomainMatch.CharLimit = 256
+
+	AltSubject := textinput.New()
+	AltSubject.Placeholder = "e.g. DNS:radius.example.edu, ..."
+	AltSubject.Prompt = ""
+	AltSubject.Width = 32
+	AltSubject.CharLimit = 512
+
 	CaCert := textinput.New()
 	CaCert.Placeholder = "e.g. /etc/ssl/certs/ca.pem"
 	CaCert.Prompt = ""
 	CaCert.Width = 32
 	CaCert.CharLimit = 512
 
+	ClientCert := textinput.New()
+	ClientCert.Placeholder = "TLS only: client.pem or bundle.p12"
+	ClientCert.Prompt = ""
+	ClientCert.Width = 32
+	ClientCert.CharLimit = 512
+
+	PrivateKey := textinput.New()
+	PrivateKey.Placeholder = "TLS only: client.key (not for .p12)"
+	PrivateKey.Prompt = ""
+	PrivateKey.Width = 32
+	PrivateKey.CharLimit = 512
+
+	KeyPassword := textinput.New()
+	KeyPassword.Placeholder = "Private key / .p12 password"
+	KeyPassword.Prompt = ""
+	KeyPassword.Width = 32
+	KeyPassword.CharLimit = 256
+	KeyPassword.EchoMode = textinput.EchoPassword
+	KeyPassword.EchoCharacter = '*'
+
 	return WpaEapForm{
 		EapMethod: selector.Model{
 			Data: []any{
@@ -93,16 +168,192 @@ func ModelWpaEapForm() WpaEapForm {
 		},
 		Identity:       Identity,
 		Password:       Password,
+		AnonIdentity:   AnonIdentity,
 		CaCert:         CaCert,
+		DomainMatch:    DomainMatch,
+		AltSubject:     AltSubject,
+		ClientCert:     ClientCert,
+		PrivateKey:     PrivateKey,
+		KeyPassword:    KeyPassword,
 		focused:        0,
 		EapSelected:    false,
 		Phase2Selected: false,
 	}
 }
+// ApplyPreset fills in everything a preset knows about the network and moves
+// focus to the identity, which is all that's left besides the password.
+func (m *WpaEapForm) ApplyPreset(p common.EapPreset) {
+	m.preset = p.Name
+	if selectOption(&m.EapMethod, p.Eap) {
+		m.EapSelected = true
+	}
+	if p.Phase2 != "" && selectOption(&m.Phase2Auth, p.Phase2) {
+		m.Phase2Selected = true
+	}
+	m.AnonIdentity.SetValue(p.AnonIdentity)
+	m.CaCert.SetValue(p.CaCert)
+	m.SystemCA = p.SystemCA
+	m.DomainMatch.SetValue(p.DomainMatch)
+	m.AltSubject.SetValue(p.AltSubject)
+	for i, v := range peapVersions {
+		if v.Value == p.PeapVersion {
+			m.PeapVersion = i
+		}
+	}
+	m.setFocus(eapFieldIdentity)
+}
+
+// selectOption picks the option named value in a selector as if the user had
+// chosen it.
+func selectOption(s *selector.Model, value string) bool {
+	for i, obj := range s.Data {
+		if option, ok := obj.(EAPMethod); ok && strings.EqualFold(option.Type, value) {
+			s.Update(nil) // no-op unless the selector hasn't been initialised yet
+			s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(fmt.Sprint(i + 1))})
+			s.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			return true
+		}
+	}
+	return false
+}
+
 func (m WpaEapForm) Init() tea.Cmd {
 	return textinput.Blink
 }
 
+func (m WpaEapForm) method() string {
+	if selected, ok := m.EapMethod.Selected().(EAPMethod); ok {
+		return selected.Type
+	}
+	return ""
+}
+
+// visible reports whether a field applies to the selected EAP method.
+func (m WpaEapForm) visible(field int) bool {
+	method := m.method()
+	usesCerts := method != "PWD"
+	tunneled := method == "PEAP" || method == "TTLS"
+
+	switch field {
+	case eapFieldPhase2, eapFieldAnonIdentity:
+		return tunneled
+	case eapFieldPassword:
+		return method != "TLS"
+	case eapFieldSystemCA, eapFieldDomainMatch, eapFieldAltSubject:
+		return usesCerts
+	case eapFieldCaCert:
+		return usesCerts && !m.SystemCA
+	case eapFieldPeapVersion, eapFieldPeapLabel:
+		return method == "PEAP"
+	case eapFieldClientCert, eapFieldPrivateKey, eapFieldKeyPassword:
+		return method == "TLS"
+	}
+	return true
+}
+
+func (m *WpaEapForm) inputs() map[int]*textinput.Model {
+	return map[int]*textinput.Model{
+		eapFieldIdentity:     &m.Identity,
+		eapFieldAnonIdentity: &m.AnonIdentity,
+		eapFieldPassword:     &m.Password,
+		eapFieldCaCert:       &m.CaCert,
+		eapFieldDomainMatch:  &m.DomainMatch,
+		eapFieldAltSubject:   &m.AltSubject,
+		eapFieldClientCert:   &m.ClientCert,
+		eapFieldPrivateKey:   &m.PrivateKey,
+		eapFieldKeyPassword:  &m.KeyPassword,
+	}
+}
+
+// setFocus moves focus to a field and updates the input and selector styles.
+func (m *WpaEapForm) setFocus(field int) {
+	m.focused = field
+	for idx, input := range m.inputs() {
+		if idx == field {
+			input.Focus()
+		} else {
+			input.Blur()
+		}
+	}
+	m.EapMethod.SelectedFunc = unselectedFunc
+	m.Phase2Auth.SelectedFunc = unselectedFunc
+	switch field {
+	case eapFieldMethod:
+		m.EapMethod.SelectedFunc = selectedFunc
+	case eapFieldPhase2:
+		m.Phase2Auth.SelectedFunc = selectedFunc
+	}
+}
+
+// moveFocus steps to the next (or previous) field that applies.
+func (m *WpaEapForm) moveFocus(step int) {
+	next := m.focused
+	for {
+		next = (next + step + eapFieldCount) % eapFieldCount
+		if m.visible(next) {
+			break
+		}
+	}
+	m.setFocus(next)
+}
+
+func (m WpaEapForm) config() map[string]string {
+	config := map[string]string{
+		"ssid":                 m.SSIDSelected,
+		"eap":                  m.method(),
+		"identity":             m.Identity.Value(),
+		"password":             m.Password.Value(),
+		"anonymous_identity":   m.AnonIdentity.Value(),
+		"ca_cert":              m.CaCert.Value(),
+		"domain_suffix_match":  m.DomainMatch.Value(),
+		"altsubject_matches":   m.AltSubject.Value(),
+		"client_cert":          m.ClientCert.Value(),
+		"private_key":          m.PrivateKey.Value(),
+		"private_key_password": m.KeyPassword.Value(),
+	}
+	if m.visible(eapFieldPhase2) {
+		config["phase2-auth"] = m.Phase2Auth.Selected().(EAPMethod).Type
+	}
+	if m.SystemCA {
+		config["system_ca_certs"] = "true"
+	}
+	if m.visible(eapFieldPeapVersion) {
+		config["peap_version"] = peapVersions[m.PeapVersion].Value
+		if m.PeapLabel {
+			config["peap_label"] = "1"
+		}
+	}
+	// Drop whatever the selected method doesn't use
+	hidden := map[string]int{
+		"password":             eapFieldPassword,
+		"anonymous_identity":   eapFieldAnonIdentity,
+		"ca_cert":              eapFieldCaCert,
+		"domain_suffix_match":  eapFieldDomainMatch,
+		"altsubject_matches":   eapFieldAltSubject,
+		"client_cert":          eapFieldClientCert,
+		"private_key":          eapFieldPrivateKey,
+		"private_key_password": eapFieldKeyPassword,
+	}
+	for key, field := range hidden {
+		if !m.visible(field) {
+			delete(config, key)
+		}
+	}
+	return config
+}
+
+// serverUnverified reports whether nothing would stop a rogue access point
+// from impersonating the network: no CA to check the server certificate
+// against and no name to match it to.
+func (m WpaEapForm) serverUnverified() bool {
+	if !m.visible(eapFieldSystemCA) {
+		return false
+	}
+	noCA := !m.SystemCA && strings.TrimSpace(m.CaCert.Value()) == ""
+	noMatch := strings.TrimSpace(m.DomainMatch.Value()) == "" && strings.TrimSpace(m.AltSubject.Value()) == ""
+	return noCA && noMatch
+}
+
 func (m WpaEapForm) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	var cmd tea.Cmd
@@ -113,58 +364,43 @@ func (m WpaEapForm) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch key.String() {
 		case "enter":
 			switch m.focused {
-			case 0:
+			case eapFieldMethod:
 				m.EapSelected = true
-			case 1:
+			case eapFieldPhase2:
 				m.Phase2Selected = true
 			}
 		// --- focus switching ---
 		case "tab", "shift+tab":
 			if key.String() == "shift+tab" {
-				m.focused = (m.focused + 5) % 6
+				m.moveFocus(-1)
 			} else {
-				m.focused = (m.focused + 1) % 6
+				m.moveFocus(1)
 			}
+			// Don't pass the tab key to the component itself
+			return m, nil
 
-			// Update focus state for text inputs
-			m.Identity.Blur()
-			m.Password.Blur()
-			m.CaCert.Blur()
-
+		// --- toggles and choices ---
+		case " ", "left", "right":
 			switch m.focused {
-			case 0:
-				m.EapMethod.SelectedFunc = selectedFunc
-				m.Phase2Auth.SelectedFunc = unselectedFunc
-			case 1:
-				m.EapMethod.SelectedFunc = unselectedFunc
-				m.Phase2Auth.SelectedFunc = selectedFunc
-			case 2:
-				m.Identity.Focus()
-				m.EapMethod.SelectedFunc = unselectedFunc
-				m.Phase2Auth.SelectedFunc = unselectedFunc
-			case 3:
-				m.Password.Focus()
-			case 4:
-				m.CaCert.Focus()
+			case eapFieldSystemCA:
+				m.SystemCA = !m.SystemCA
+				return m, nil
+			case eapFieldPeapLabel:
+				m.PeapLabel = !m.PeapLabel
+				return m, nil
+			case eapFieldPeapVersion:
+				if key.String() == "left" {
+					m.PeapVersion = (m.PeapVersion + len(peapVersions) - 1) % len(peapVersions)
+				} else {
+					m.PeapVersion = (m.PeapVersion + 1) % len(peapVersions)
+				}
+				return m, nil
 			}
-			// Don't pass the tab key to the component itself
-			return m, nil
 
 		// --- select all (Ctrl+A) ---
 		case "ctrl+a":
-			switch m.focused {
-			case 2:
-				ti := m.Identity
-				ti.SetCursor(len(ti.Value()))
-				m.Identity = ti
-			case 3:
-				ti := m.Password
-				ti.SetCursor(len(ti.Value()))
-				m.Password = ti
-			case 4:
-				ti := m.CaCert
-				ti.SetCursor(len(ti.Value()))
-				m.CaCert = ti
+			if input, ok := m.inputs()[m.focused]; ok {
+				input.SetCursor(len(input.Value()))
 			}
 			return m, nil
 		case "esc", "ctrl+c":
@@ -186,48 +422,41 @@ func (m WpaEapForm) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	// 2. Pass *all* messages to text inputs; they handle focus internally.
-	m.Identity, cmd = m.Identity.Update(msg)
-	cmds = append(cmds, cmd)
-	m.Password, cmd = m.Password.Update(msg)
-	cmds = append(cmds, cmd)
-	m.CaCert, cmd = m.CaCert.Update(msg)
-	cmds = append(cmds, cmd)
+	for _, input := range m.inputs() {
+		*input, cmd = input.Update(msg)
+		cmds = append(cmds, cmd)
+	}
 
 	// 3. Only pass key-press messages to the *focused* selector.
 	if _, ok := msg.(tea.KeyMsg); ok {
 		switch m.focused {
-		case 0:
+		case eapFieldMethod:
 			sm, cmd = m.EapMethod.Update(msg)
 			m.EapMethod = *sm
 			cmds = append(cmds, cmd)
 
 			if msg.(tea.KeyMsg).String() == "enter" {
-				m.focused++
-				m.EapMethod.SelectedFunc = unselectedFunc
-				m.Phase2Auth.SelectedFunc = selectedFunc
+				m.moveFocus(1)
 			}
-		case 1:
+		case eapFieldPhase2:
 			sm, cmd = m.Phase2Auth.Update(msg)
 			m.Phase2Auth = *sm
 			cmds = append(cmds, cmd)
 
 			if msg.(tea.KeyMsg).String() == "enter" {
-				m.EapMethod.SelectedFunc = unselectedFunc
-				m.Phase2Auth.SelectedFunc = unselectedFunc
-				m.Identity.Focus()
-				m.focused++
+				m.moveFocus(1)
+			}
+		case eapFieldSubmit:
+			if msg.(tea.KeyMsg).String() != "enter" {
+				break
 			}
-		case 5:
 			// Submit form
-			config := map[string]string{
-				"ssid":				 m.SSIDSelected,
-				"eap":         m.EapMethod.Selected().(EAPMethod).Type,
-				"phase2-auth": m.Phase2Auth.Selected().(EAPMethod).Type,
-				"identity":    m.Identity.Value(),
-				"password":    m.Password.Value(),
-				"ca_cert":     m.CaCert.Value(),
+			config := m.config()
+			// Catch missing or unreadable files here, while the form can still be fixed
+			if m.err = validateEapFiles(config); m.err != nil {
+				return m, tea.Batch(cmds...)
 			}
-			
+
 			// Send the message with the data back to the parent
 			return m, func() tea.Msg {
 				return common.SubmitEapFormMsg{Config: config}
@@ -262,39 +491,89 @@ func (m WpaEapForm) View() string {
 		BorderForeground(lipgloss.Color("#9cca69")).
 		Padding(0, 1)
 
-	// Always render all text boxes, just change the style.
-	EapMethodLabel := inactiveLabelStyle.Render("EAP Method:")
-	phase2Label := inactiveLabelStyle.Render("Phase 2 (inner-auth):")
+	warningStyle := lipgloss.NewStyle().
+		Bold(true).
+		Width(36).
+		Foreground(lipgloss.Color("#e06c75"))
+
+	label := func(field int, text string) string {
+		if m.focused == field {
+			return activeLabelStyle.Render(text)
+		}
+		return inactiveLabelStyle.Render(text)
+	}
+	box := func(field int, input textinput.Model) string {
+		if m.focused == field {
+			return activeBorderStyle.Render(input.View())
+		}
+		return inactiveBorderStyle.Render(input.View())
+	}
+	check := func(field int, text string, on bool) string {
+		mark := "[ ]"
+		if on {
+			mark = "[x]"
+		}
+		return label(field, mark+" "+text)
+	}
 
-	IdentityLabel := inactiveLabelStyle.Render("Identity:")
-	IdentityBox := inactiveBorderStyle.Render(m.Identity.View())
+	// We perform the string alterations below the remove the spacing reserved for the header and footer of the selector
+	eapStr := strings.TrimSuffix(strings.Replace(m.EapMethod.View(), "\n", "", 2), "\n")
+	phase2Str := strings.TrimSuffix(strings.Replace(m.Phase2Auth.View(), "\n", "", 2), "\n")
+	if m.EapSelected {
+		eapStr = m.EapMethod.View()
+	}
+	if m.Phase2Selected {
+		phase2Str = m.Phase2Auth.View()
+	}
 
-	PasswordLabel := inactiveLabelStyle.Render("\nPassword:")
-	PasswordBox := inactiveBorderStyle.Render(m.Password.View())
+	sections := []string{}
+	if m.preset != "" {
+		sections = append(sections, inactiveLabelStyle.Render(fmt.Sprintf("Preset: %s", m.preset)), "")
+	}
+	sections = append(sections, label(eapFieldMethod, "EAP Method:"), eapStr)
+	if m.visible(eapFieldPhase2) {
+		sections = append(sections, label(eapFieldPhase2, "Phase 2 (inner-auth):"), phase2Str)
+	}
 
-	CaCertLabel := inactiveLabelStyle.Render("\nCA Certificate:")
-	CaCertBox := inactiveBorderStyle.Render(m.CaCert.View())
+	fields := []struct {
+		field int
+		text  string
+		input textinput.Model
+	}{
+		{eapFieldIdentity, "Identity:", m.Identity},
+		{eapFieldAnonIdentity, "Anonymous Identity:", m.AnonIdentity},
+		{eapFieldPassword, "Password:", m.Password},
+		{eapFieldSystemCA, "", textinput.Model{}},
+		{eapFieldCaCert, "CA Certificate:", m.CaCert},
+		{eapFieldDomainMatch, "Domain Suffix Match:", m.DomainMatch},
+		{eapFieldAltSubject, "Alt Subject Matches:", m.AltSubject},
+		{eapFieldPeapVersion, "", textinput.Model{}},
+		{eapFieldPeapLabel, "", textinput.Model{}},
+		{eapFieldClientCert, "Client Certificate:", m.ClientCert},
+		{eapFieldPrivateKey, "Private Key:", m.PrivateKey},
+		{eapFieldKeyPassword, "Key Password:", m.KeyPassword},
+	}
+	for _, f := range fields {
+		if !m.visible(f.field) {
+			continue
+		}
+		switch f.field {
+		case eapFieldSystemCA:
+			sections = append(sections, check(f.field, "Use system CA bundle (space)", m.SystemCA))
+		case eapFieldPeapVersion:
+			sections = append(sections, label(f.field, "PEAP Version (←/→): "+peapVersions[m.PeapVersion].Label))
+		case eapFieldPeapLabel:
+			sections = append(sections, check(f.field, "Force new PEAP label (space)", m.PeapLabel))
+		default:
+			sections = append(sections, label(f.field, f.text), box(f.field, f.input))
+		}
+	}
 
 	submitLabel := inactiveBorderStyle.
 		Width(36).
 		Align(lipgloss.Center).
 		Render("Connect")
-
-	switch m.focused {
-	case 0:
-		EapMethodLabel = activeLabelStyle.Render("EAP Method:")
-	case 1:
-		phase2Label = activeLabelStyle.Render("Phase 2 (inner-auth):")
-	case 2:
-		IdentityLabel = activeLabelStyle.Render("Identity:")
-		IdentityBox = activeBorderStyle.Render(m.Identity.View())
-	case 3:
-		PasswordLabel = activeLabelStyle.Render("\nPassword:")
-		PasswordBox = activeBorderStyle.Render(m.Password.View())
-	case 4:
-		CaCertLabel = activeLabelStyle.Render("\nCA Certificate:")
-		CaCertBox = activeBorderStyle.Render(m.CaCert.View())
-	case 5:
+	if m.focused == eapFieldSubmit {
 		submitLabel = activeBorderStyle.
 			Width(36).
 			Bold(true).
@@ -302,36 +581,49 @@ func (m WpaEapForm) View() string {
 			BorderForeground(lipgloss.Color("#cda162")).
 			Render("Connect")
 	}
-	// --- END NEW LOGIC ---
-	eapStr := strings.TrimSuffix(strings.Replace(m.EapMethod.View(), "\n", "", 2), "\n")
-	phase2Str := strings.TrimSuffix(strings.Replace(m.Phase2Auth.View(), "\n", "", 2), "\n")
-	if m.EapSelected {
-		eapStr = m.EapMethod.View()
+	sections = append(sections, submitLabel)
+
+	if m.serverUnverified() {
+		sections = append(sections, warningStyle.Render(
+			"⚠ WARNING: no CA certificate and no domain match. "+
+				"Any access point can pretend to be this network and capture your credentials."))
 	}
-	if m.Phase2Selected {
-		phase2Str = m.Phase2Auth.View()
+	if m.err != nil {
+		sections = append(sections, warningStyle.Bold(false).Render(m.err.Error()))
 	}
 
-	// We perform the string alterations below the remove the spacing reserved for the header and footer of the selector
-	content := lipgloss.JoinVertical(lipgloss.Left,
-		EapMethodLabel,
-		eapStr,
-
-		phase2Label,
-		phase2Str,
-
-		IdentityLabel,
-		IdentityBox,
-
-		PasswordLabel,
-		PasswordBox,
+	return formStyle.Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+}
 
-		CaCertLabel,
-		CaCertBox,
+// validateEapFiles checks the certificate and key files the chosen method
+// needs before handing them to NetworkManager.
+func validateEapFiles(config map[string]string) error {
+	if ca := config["ca_cert"]; ca != "" {
+		if err := common.ValidateCertificate(ca); err != nil {
+			return fmt.Errorf("CA certificate: %w", err)
+		}
+	}
+	if config["eap"] != "TLS" {
+		return nil
+	}
 
-		submitLabel,
-	)
-	return formStyle.Render(content)
+	cert, key, password := config["client_cert"], config["private_key"], config["private_key_password"]
+	if cert == "" {
+		return fmt.Errorf("TLS needs a client certificate")
+	}
+	if common.IsPKCS12(cert) {
+		if key != "" && key != cert {
+			return fmt.Errorf("a .p12 bundle already holds the private key, leave Private Key empty")
+		}
+		return common.ValidatePKCS12(cert, password)
+	}
+	if err := common.ValidateCertificate(cert); err != nil {
+		return fmt.Errorf("client certificate: %w", err)
+	}
+	if key == "" {
+		return fmt.Errorf("TLS needs the private key for the client certificate")
+	}
+	return common.ValidatePrivateKey(key, password)
 }
 
 func selectedFunc(m selector.Model, obj any, gdIndex int) string {
@@ -7,6 +7,8 @@ import (
 	"netpala/models"
 	"netpala/network"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -30,10 +32,36 @@ type NetpalaData struct {
 	Form           	models.WpaEapForm
 	Overlay        	overlay.Model
 	Confirmation   	models.Confirmation
+	Hotspot        	models.HotspotForm
+	HotspotPolling 	bool	// a hotspot client poll is scheduled, so only one loop runs
+	QRView         	models.QRView
+	SecretView     	models.SecretView
+	WireGuard      	models.WireGuardEditor
+	WireGuardPath  	godbus.ObjectPath	// profile being edited in the WireGuard editor
+	VpnPicker      	models.VpnPicker
+	APList         	models.AccessPointList
+	Wireless       	models.WirelessEditor
+	Channels       	models.ChannelView
+	Checkpoint     	models.CheckpointPrompt
+	CheckpointPath 	godbus.ObjectPath	// checkpoint waiting to be kept or rolled back
+	History        	models.HistoryView
+	Session        	common.HistoryEntry	// "connected" entry of the current Wi-Fi connection
+	Attempt        	common.HistoryEntry	// network the Wi-Fi device is connecting to
+	VpnHooks       	map[string]string	// last hook event run for each VPN, so up and down alternate
+	EapPresets     	[]common.EapPreset	// user presets first, then the built-in ones
+	SignalHistory  	common.SignalHistory	// signal samples per BSSID for this session
+	Throughput     	common.Throughput	// byte counters of the Wi-Fi device
+	StatsPath      	godbus.ObjectPath	// device statistics were requested for
+	StatsRestoreMs 	uint32	// its RefreshRateMs before we changed it
+	Connectivity   	common.ConnectivityStatus
+	AirplaneRestore	*common.RadioState	// radios to switch back on when leaving airplane mode
+	UndoStack      	[]common.ProfileSnapshot	// profiles deleted this session, most recent last
 
 	SelectedNetwork	common.ScannedNetwork
 	IsTyping       	bool
-	PopupState     	int	// -1: no popup, 0: form, 1: confirm
+	InputAction    	string	// what the status bar input is for: "password", "import" or "rename"
+	PopupState     	int	// -1: no popup, 0: form, 1: confirm, 2: hotspot, 3: qr code, 4: secret, 5: wireguard, 6: vpn picker, 7: access points, 8: wireless settings, 9: channels, 10: checkpoint, 11: history
+	ConfirmAction  	string	// what the confirmation popup is asking about
 
 	InitialLoadComplete bool
 	Conn                *godbus.Conn
@@ -49,6 +77,8 @@ func loadInitialData(Conn *godbus.Conn) tea.Cmd {
 		vpns := network.GetVpnData(Conn)
 		known := network.GetKnownNetworks(Conn)
 		scanned := network.GetScannedNetworks(Conn)
+		presets, presetsErr := network.LoadEapPresets()
+		connectivity := network.GetConnectivity(Conn)
 
 		// Step 2: Perform the filtering logic on the initial data.
 		knownSSIDs := make(map[string]struct{})
@@ -69,6 +99,8 @@ func loadInitialData(Conn *godbus.Conn) tea.Cmd {
 			func() tea.Msg { return common.KnownNetworksUpdateMsg(known) },
 			func() tea.Msg { return common.ScannedNetworksUpdateMsg(filteredScanned) },
 			func() tea.Msg { return common.VpnUpdateMsg(vpns) },
+			func() tea.Msg { return common.EapPresetsMsg{Presets: presets, Err: presetsErr} },
+			func() tea.Msg { return common.ConnectivityUpdateMsg(connectivity) },
 		}
 	}
 }
@@ -91,6 +123,22 @@ func (m *NetpalaData) FilterKnownFromScanned() {
 	m.ScannedNetworks = filteredScanned
 }
 
+// isDataMsg reports whether a message carries NetworkManager data that should
+// reach the main model even while a popup is open.
+func isDataMsg(msg tea.Msg) bool {
+	switch msg.(type) {
+	case common.DeviceUpdateMsg, common.VpnUpdateMsg, common.KnownNetworksUpdateMsg,
+		common.ScannedNetworksUpdateMsg, common.PerformScanRefreshMsg, common.PeriodicRefreshMsg,
+		common.ErrMsg, common.NoticeMsg, common.ClearNoticeMsg, common.EapPresetsMsg,
+		common.ChangeFailedMsg, common.StatisticsEnabledMsg, common.StatisticsMsg, common.ConnectivityUpdateMsg,
+		common.AirplaneModeMsg, common.CheckpointCreatedMsg,
+		common.ProfileDeletedMsg, common.UndoFailedMsg, common.DeviceStateMsg,
+		common.VpnStateMsg, common.ScanCompletedMsg, tea.WindowSizeMsg:
+		return true
+	}
+	return false
+}
+
 func NetpalaModel() NetpalaData {
 	Conn, err := godbus.SystemBus()
 	if err != nil {
@@ -107,8 +155,10 @@ func NetpalaModel() NetpalaData {
 		"type='signal',interface='org.freedesktop.NetworkManager',member='DeviceRemoved'",
 		"type='signal',interface='org.freedesktop.NetworkManager.Settings',member='NewConnection'",
 		"type='signal',interface='org.freedesktop.NetworkManager.Settings',member='ConnectionRemoved'",
+		"type='signal',interface='org.freedesktop.NetworkManager.Settings.Connection',member='Updated'",
 		"type='signal',interface='org.freedesktop.NetworkManager.Device.Wireless',member='AccessPointAdded'",
 		"type='signal',interface='org.freedesktop.NetworkManager.Device.Wireless',member='AccessPointRemoved'",
+		"type='signal',interface='org.freedesktop.NetworkManager.VPN.Connection',member='VpnStateChanged'",
 	}
 	busObject := Conn.BusObject()
 	for _, rule := range rules {
@@ -128,6 +178,7 @@ func NetpalaModel() NetpalaData {
 		VpnData:         []common.VpnConnection{},
 		KnownNetworks:   []common.KnownNetwork{},
 		ScannedNetworks: []common.ScannedNetwork{},
+		SignalHistory:   common.SignalHistory{},
 		
 		Tables:          models.TablesModel{},
 		StatusBar:       models.ModelStatusBar(),
@@ -161,6 +212,15 @@ func (m NetpalaData) Init() tea.Cmd {
 func (m NetpalaData) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	// The client poll stops once the hotspot popup is closed; the next
+	// one is only scheduled while it is open.
+	if _, ok := msg.(common.HotspotClientsUpdateMsg); ok {
+		m.HotspotPolling = false
+		if m.PopupState != 2 {
+			return m, nil
+		}
+	}
+
 	switch m.PopupState {
 	case 0:
 		// Handle the EAP form popup state
@@ -195,7 +255,7 @@ func (m NetpalaData) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			// Add the EAP connection config from the message
 			// and combine it with the form's init command.
-			eapCmd := dbus.AddAndConnectEAPCmd(m.Conn, msg.Config, wifiDevice.Path)
+			eapCmd := dbus.AddAndConnectEAPCmd(m.Conn, msg.Config, m.SelectedNetwork.Security, wifiDevice.Path)
 			return m, tea.Batch(formCmd, eapCmd)
 		}	
 
@@ -211,11 +271,18 @@ func (m NetpalaData) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Confirmation = models.ModelConfirmation() // Reset
 
 			if msg.Value { // User confirmed
-				// Delete the known network
 				// NOTE: Ensure m.SelectedNetwork holds the correct data before entering state 1
-				deleteCmd := dbus.DeleteConnectionCmd(m.Conn, m.SelectedNetwork.Path)
-				// Return delete command AND re-arm listener
-				return m, tea.Batch(deleteCmd, dbus.WaitForDBusSignal(m.Conn, m.DBusSignals))
+				switch m.ConfirmAction {
+				case "reveal":
+					revealCmd := dbus.RevealSecretCmd(m.Conn, m.SelectedNetwork.Path, m.SelectedNetwork.SSID)
+					return m, tea.Batch(revealCmd, dbus.WaitForDBusSignal(m.Conn, m.DBusSignals))
+				default:
+					// Delete the known network
+					deleteCmd := m.changeProfile(m.SelectedNetwork.Path, fmt.Sprintf("deleted '%s'", m.SelectedNetwork.SSID),
+						dbus.DeleteConnectionCmd(m.Conn, m.SelectedNetwork.Path))
+					// Return delete command AND re-arm listener
+					return m, tea.Batch(deleteCmd, dbus.WaitForDBusSignal(m.Conn, m.DBusSignals))
+				}
 			} else { // User cancelled
 				// Just return and re-arm listener
 				return m, dbus.WaitForDBusSignal(m.Conn, m.DBusSignals)
@@ -229,17 +296,187 @@ func (m NetpalaData) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Return the confirmation model and any command it produced
 			return m, cmd
 		}
+	case 2:
+		// Handle the hotspot popup state
+		wifiDevice := m.Hotspot.Device
+		switch msg := msg.(type) {
+		case common.ExitFormMsg:
+			m.PopupState = -1
+			return m, nil
+		case common.SubmitHotspotFormMsg:
+			return m, m.switchConnection(wifiDevice, "started the hotspot", dbus.StartHotspotCmd(m.Conn, msg.Config, wifiDevice))
+		case common.StopHotspotMsg:
+			return m, m.switchConnection(wifiDevice, "stopped the hotspot", dbus.StopHotspotCmd(m.Conn, wifiDevice.Path))
+		case common.HotspotClientsUpdateMsg:
+			var newHotspot tea.Model
+			newHotspot, cmd = m.Hotspot.Update(msg)
+			m.Hotspot = newHotspot.(models.HotspotForm)
+			m.HotspotPolling = true
+			return m, tea.Batch(cmd, dbus.HotspotClientsCmd(wifiDevice.Name))
+		default:
+			if !isDataMsg(msg) {
+				var newHotspot tea.Model
+				newHotspot, cmd = m.Hotspot.Update(msg)
+				m.Hotspot = newHotspot.(models.HotspotForm)
+				return m, cmd
+			}
+		}
+	case 3:
+		// Handle the QR code popup state
+		switch msg := msg.(type) {
+		case common.ExitFormMsg:
+			m.PopupState = -1
+			return m, nil
+		case tea.KeyMsg:
+			var newQRView tea.Model
+			newQRView, cmd = m.QRView.Update(msg)
+			m.QRView = newQRView.(models.QRView)
+			return m, cmd
+		}
+	case 4:
+		// Handle the saved secret popup state
+		switch msg := msg.(type) {
+		case common.ExitFormMsg:
+			m.PopupState = -1
+			m.SecretView = models.SecretView{} // Don't keep the secret around
+			return m, nil
+		case tea.KeyMsg:
+			var newSecretView tea.Model
+			newSecretView, cmd = m.SecretView.Update(msg)
+			m.SecretView = newSecretView.(models.SecretView)
+			return m, cmd
+		}
+	case 5:
+		// Handle the WireGuard editor popup state
+		switch msg := msg.(type) {
+		case common.ExitFormMsg:
+			m.PopupState = -1
+			m.WireGuard = models.WireGuardEditor{} // Don't keep the keys around
+			return m, nil
+		case common.SubmitWireGuardMsg:
+			m.PopupState = -1
+			m.WireGuard = models.WireGuardEditor{}
+			return m, m.changeProfile(m.WireGuardPath, "edited WireGuard settings",
+				dbus.SaveWireGuardCmd(m.Conn, m.WireGuardPath, msg.Config))
+		default:
+			if !isDataMsg(msg) {
+				var newEditor tea.Model
+				newEditor, cmd = m.WireGuard.Update(msg)
+				m.WireGuard = newEditor.(models.WireGuardEditor)
+				return m, cmd
+			}
+		}
+	case 8:
+		// Handle the advanced wireless settings popup state
+		switch msg := msg.(type) {
+		case common.ExitFormMsg:
+			m.PopupState = -1
+			return m, nil
+		case common.SubmitWirelessMsg:
+			m.PopupState = -1
+			return m, m.changeProfile(m.SelectedNetwork.Path, "edited Wi-Fi settings",
+				dbus.SaveWirelessCmd(m.Conn, m.SelectedNetwork.Path, msg.Config))
+		default:
+			if !isDataMsg(msg) {
+				var newEditor tea.Model
+				newEditor, cmd = m.Wireless.Update(msg)
+				m.Wireless = newEditor.(models.WirelessEditor)
+				return m, cmd
+			}
+		}
+	case 9:
+		// Handle the channel analyzer popup state
+		switch msg := msg.(type) {
+		case common.ExitFormMsg:
+			m.PopupState = -1
+			return m, nil
+		case tea.KeyMsg:
+			var newView tea.Model
+			newView, cmd = m.Channels.Update(msg)
+			m.Channels = newView.(models.ChannelView)
+			return m, cmd
+		}
+	case 6:
+		// Handle the VPN picker popup state
+		switch msg := msg.(type) {
+		case common.ExitFormMsg:
+			m.PopupState = -1
+			return m, nil
+		case common.SubmitSecondariesMsg:
+			m.PopupState = -1
+			return m, m.changeProfile(m.SelectedNetwork.Path, fmt.Sprintf("changed the VPNs of '%s'", m.SelectedNetwork.SSID),
+				dbus.SetSecondariesCmd(m.Conn, m.SelectedNetwork.Path, msg.UUIDs))
+		case tea.KeyMsg:
+			var newPicker tea.Model
+			newPicker, cmd = m.VpnPicker.Update(msg)
+			m.VpnPicker = newPicker.(models.VpnPicker)
+			return m, cmd
+		}
+	case 7:
+		// Handle the access point list popup state
+		switch msg := msg.(type) {
+		case common.ExitFormMsg:
+			m.PopupState = -1
+			return m, nil
+		case common.ConnectAccessPointMsg:
+			m.PopupState = -1
+			if len(m.DeviceData) == 0 {
+				return m, nil
+			}
+			if m.APList.Known {
+				return m, m.switchConnection(m.DeviceData[0], "switched networks", dbus.ConnectToAccessPointCmd(m.Conn, m.SelectedNetwork.Path, m.DeviceData[0].Path, msg.AP.Path))
+			}
+			m.SelectedNetwork.AccessPoint = msg.AP.Path
+			m.SelectedNetwork.BSSID = msg.AP.BSSID
+			return m.joinNetwork()
+		case common.PinBssidMsg:
+			m.PopupState = -1
+			return m, m.changeProfile(m.SelectedNetwork.Path, "changed the pinned access point",
+				dbus.PinBssidCmd(m.Conn, m.SelectedNetwork.Path, msg.BSSID))
+		case tea.KeyMsg:
+			var newList tea.Model
+			newList, cmd = m.APList.Update(msg)
+			m.APList = newList.(models.AccessPointList)
+			return m, cmd
+		}
+	case 11:
+		// Handle the connection history popup state
+		switch msg := msg.(type) {
+		case common.ExitFormMsg:
+			m.PopupState = -1
+			return m, nil
+		case tea.KeyMsg:
+			var newView tea.Model
+			newView, cmd = m.History.Update(msg)
+			m.History = newView.(models.HistoryView)
+			return m, cmd
+		}
+	case 10:
+		// Handle the checkpoint countdown popup state
+		switch msg := msg.(type) {
+		case common.SubmitCheckpointMsg:
+			m.PopupState = -1
+			path := m.CheckpointPath
+			m.CheckpointPath = ""
+			return m, dbus.FinishCheckpointCmd(m.Conn, path, m.Checkpoint.Description, msg.Keep)
+		case common.CheckpointTickMsg:
+			var newPrompt tea.Model
+			newPrompt, cmd = m.Checkpoint.Update(msg)
+			m.Checkpoint = newPrompt.(models.CheckpointPrompt)
+			return m, tea.Batch(cmd, dbus.CheckpointTickCmd())
+		case tea.KeyMsg:
+			var newPrompt tea.Model
+			newPrompt, cmd = m.Checkpoint.Update(msg)
+			m.Checkpoint = newPrompt.(models.CheckpointPrompt)
+			return m, cmd
+		}
 	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "esc":
-			m.IsTyping = false
-			m.StatusBar.Input.Placeholder = ""
-			m.StatusBar.Input.Blur()
-			m.StatusBar.Input.SetValue("")
-			return m, tea.Quit
+			return m.quit()
 		}
 	}
 
@@ -250,17 +487,32 @@ func (m NetpalaData) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "ctrl+c", "esc":
 				m.IsTyping = false
 				m.StatusBar.Input.Placeholder = ""
+				m.StatusBar.Warning = ""
 				m.StatusBar.Input.Blur()
 				m.StatusBar.Input.SetValue("")
 				return m, nil
 
 			case "enter":
-				password := m.StatusBar.Input.Value()
+				value := m.StatusBar.Input.Value()
+				if m.InputAction == "rename" && strings.TrimSpace(value) == "" {
+					m.StatusBar.Warning = "The name can't be empty"
+					return m, nil
+				}
 				m.IsTyping = false
 				m.StatusBar.Input.Placeholder = ""
+				m.StatusBar.Warning = ""
 				m.StatusBar.Input.Blur()
 				m.StatusBar.Input.SetValue("")
 
+				switch m.InputAction {
+				case "import":
+					return m, dbus.ImportConnectionCmd(m.Conn, value)
+				case "rename":
+					return m, m.changeProfile(m.SelectedNetwork.Path, fmt.Sprintf("renamed '%s'", m.SelectedNetwork.SSID),
+						dbus.RenameConnectionCmd(m.Conn, m.SelectedNetwork.Path, strings.TrimSpace(value)))
+				}
+				password := value
+
 				if len(m.DeviceData) == 0 {
 					return m, func() tea.Msg {
 						return common.ErrMsg{Err: fmt.Errorf("no wifi device found")}
@@ -279,15 +531,145 @@ func (m NetpalaData) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case common.DeviceUpdateMsg:
+		wasConnected := len(m.DeviceData) > 0 && m.DeviceData[0].State == 1
 		m.DeviceData = msg
+		for _, d := range m.DeviceData {
+			if d.Path == m.Hotspot.Device.Path {
+				m.Hotspot.Running = d.Mode == "ap"
+			}
+		}
+		if len(m.DeviceData) == 0 {
+			return m, dbus.WaitForDBusSignal(m.Conn, m.DBusSignals)
+		}
+		wifiDevice := m.DeviceData[0]
+		if wifiDevice.State == 1 && !wasConnected && m.Throughput.Path == wifiDevice.Path {
+			// A new connection starts a new session
+			m.Throughput.Reset(wifiDevice.Path)
+		}
+		if wifiDevice.Path != m.StatsPath {
+			m.StatsPath = wifiDevice.Path
+			cmds := []tea.Cmd{dbus.WaitForDBusSignal(m.Conn, m.DBusSignals), dbus.EnableStatisticsCmd(m.Conn, wifiDevice.Path)}
+			if m.Throughput.Path != "" && m.Throughput.Path != wifiDevice.Path {
+				cmds = append(cmds, dbus.RestoreStatisticsCmd(m.Conn, m.Throughput.Path, m.StatsRestoreMs))
+			}
+			return m, tea.Batch(cmds...)
+		}
+		return m, dbus.WaitForDBusSignal(m.Conn, m.DBusSignals)
+
+	case common.StatisticsEnabledMsg:
+		m.StatsRestoreMs = msg.PrevRateMs
+		m.Throughput.Reset(msg.Path)
+		m.Throughput.Record(msg.RxBytes, msg.TxBytes, time.Now())
+		return m, nil
+
+	case common.ConnectivityUpdateMsg:
+		wasPortal := m.Connectivity.Connectivity == common.ConnectivityPortal
+		m.Connectivity = common.ConnectivityStatus(msg)
+		if m.Connectivity.Connectivity == common.ConnectivityPortal && !wasPortal {
+			return m, func() tea.Msg { return common.NoticeMsg("Captive portal detected, press 'l' to sign in") }
+		}
+		return m, nil
+
+	case common.AirplaneModeMsg:
+		if msg.Enabled {
+			m.AirplaneRestore = &msg.Previous
+			return m, func() tea.Msg { return common.NoticeMsg("Airplane mode on") }
+		}
+		m.AirplaneRestore = nil
+		if len(m.DeviceData) > 0 && m.DeviceData[0].HardBlocked {
+			return m, func() tea.Msg { return common.NoticeMsg("Airplane mode off, but " + common.HardBlockHint) }
+		}
+		return m, func() tea.Msg { return common.NoticeMsg("Airplane mode off") }
+
+	case common.DeviceStateMsg:
+		return m.recordDeviceState(msg)
+
+	case common.VpnStateMsg:
+		event := ""
+		switch msg.State {
+		case common.VpnStateActivated:
+			event = "vpn-up"
+		case common.VpnStateFailed, common.VpnStateDisconnected:
+			event = "vpn-down"
+		}
+		if event == "" || m.VpnHooks[msg.Name] == event {
+			return m, nil
+		}
+		if m.VpnHooks == nil {
+			m.VpnHooks = map[string]string{}
+		}
+		m.VpnHooks[msg.Name] = event
+		hook := common.HookEvent{Name: event, VPN: msg.Name, IP: msg.IP}
+		if event == "vpn-down" && msg.WireGuard {
+			hook.Reason = common.DeviceStateReason(msg.Reason)
+		} else if event == "vpn-down" {
+			hook.Reason = common.VpnFailureReason(msg.Reason)
+		}
+		return m, dbus.RunHooksCmd(hook)
+
+	case common.ScanCompletedMsg:
+		for _, d := range m.DeviceData {
+			if d.Path == msg.Path {
+				return m, dbus.RunHooksCmd(common.HookEvent{Name: "scan-completed", Device: d.Name})
+			}
+		}
+		return m, nil
+
+	case common.HistoryMsg:
+		m.History = models.ModelHistoryView(msg.Entries)
+		m.PopupState = 11
+
+		m.Overlay = updateOverlayModel(m, &m.History)
+		if msg.Err != nil {
+			return m, func() tea.Msg { return common.NoticeMsg(msg.Err.Error()) }
+		}
+		return m, nil
+
+	case common.ProfileDeletedMsg:
+		m.UndoStack = append(m.UndoStack, msg.Snapshot)
+		return m, func() tea.Msg {
+			return common.NoticeMsg(fmt.Sprintf("Deleted '%s', press 'u' to undo", msg.Snapshot.ID))
+		}
+
+	case common.UndoFailedMsg:
+		// Keep it so the undo can be retried
+		m.UndoStack = append(m.UndoStack, msg.Snapshot)
+		return m, func() tea.Msg { return common.NoticeMsg(msg.Err.Error()) }
+
+	case common.CheckpointCreatedMsg:
+		m.Checkpoint = models.ModelCheckpointPrompt(msg.Description, msg.Deadline)
+		m.CheckpointPath = msg.Path
+		m.PopupState = 10
+
+		m.Overlay = updateOverlayModel(m, &m.Checkpoint)
+		return m, dbus.CheckpointTickCmd()
+
+	case common.StatisticsMsg:
+		if msg.Path == m.Throughput.Path {
+			m.Throughput.Record(msg.RxBytes, msg.TxBytes, time.Now())
+		}
+		// Counter updates come from the listener, so re-arm it
 		return m, dbus.WaitForDBusSignal(m.Conn, m.DBusSignals)
 
 	case common.VpnUpdateMsg:
 		m.VpnData = msg
+		if m.selectedBox == 2 {
+			// Keep the selection valid after a VPN is deleted
+			if len(m.VpnData) == 0 {
+				m.selectedBox = 3
+				m.SelectedEntry = 0
+			} else if m.SelectedEntry >= len(m.VpnData) {
+				m.SelectedEntry = len(m.VpnData) - 1
+			}
+		}
 
 	case common.KnownNetworksUpdateMsg:
 		m.FilterKnownFromScanned()
 		m.KnownNetworks = msg
+		now := time.Now()
+		for _, n := range msg {
+			m.SignalHistory.Record(n.BSSID, n.Signal, now)
+		}
 
 		return m, dbus.WaitForDBusSignal(m.Conn, m.DBusSignals)
 
@@ -303,6 +685,10 @@ func (m NetpalaData) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// This is the actual data from a completed scan.
 		m.ScannedNetworks = msg
 		m.FilterKnownFromScanned()
+		now := time.Now()
+		for _, n := range msg {
+			m.SignalHistory.Record(n.BSSID, n.Signal, now)
+		}
 
 		// No need to re-arm listener here, as it's handled by the debounce logic.
 		return m, nil
@@ -315,6 +701,101 @@ func (m NetpalaData) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.Err = msg.Err
 		return m, nil		
 
+	case common.AccessPointsMsg:
+		if msg.SSID != m.SelectedNetwork.SSID {
+			return m, nil
+		}
+		now := time.Now()
+		for _, ap := range msg.APs {
+			m.SignalHistory.Record(ap.BSSID, ap.Signal, now)
+		}
+		// Only saved profiles have a settings path
+		known := m.SelectedNetwork.Path != ""
+		pinned, current := "", ""
+		for _, k := range m.KnownNetworks {
+			if k.Path == m.SelectedNetwork.Path {
+				pinned = k.PinnedBSSID
+			}
+		}
+		if len(m.DeviceData) > 0 {
+			current = m.DeviceData[0].CurrentBSSID
+		}
+		m.APList = models.ModelAccessPointList(msg.SSID, msg.APs, m.SignalHistory, known, pinned, current)
+		m.PopupState = 7
+
+		m.Overlay = updateOverlayModel(m, &m.APList)
+		return m, nil
+
+	case common.EapPresetsMsg:
+		m.EapPresets = msg.Presets
+		if msg.Err != nil {
+			return m, func() tea.Msg { return common.NoticeMsg(msg.Err.Error()) }
+		}
+		return m, nil
+
+	case common.NoticeMsg:
+		m.StatusBar.Notice = string(msg)
+		return m, dbus.ClearNoticeCmd(msg)
+
+	case common.ChangeFailedMsg:
+		notice := common.NoticeMsg(msg.Err.Error())
+		m.StatusBar.Notice = string(notice)
+		return m, dbus.ClearNoticeCmd(notice)
+
+	case common.ClearNoticeMsg:
+		if m.StatusBar.Notice == string(msg) {
+			m.StatusBar.Notice = ""
+		}
+		return m, nil
+
+	case common.SecretMsg:
+		m.SecretView = models.ModelSecretView(msg.SSID, msg.Label, msg.Secret)
+		m.PopupState = 4
+
+		m.Overlay = updateOverlayModel(m, &m.SecretView)
+		return m, nil
+
+	case common.WireGuardLoadedMsg:
+		m.WireGuard = models.ModelWireGuardEditor(msg.Name, msg.Config)
+		m.WireGuardPath = msg.Path
+		m.PopupState = 5
+
+		m.Overlay = updateOverlayModel(m, &m.WireGuard)
+		return m, m.WireGuard.Init()
+
+	case common.ChannelScanMsg:
+		now := time.Now()
+		for _, ap := range msg {
+			m.SignalHistory.Record(ap.BSSID, ap.Signal, now)
+		}
+		currentFreq := 0
+		if len(m.DeviceData) > 0 {
+			currentFreq = m.DeviceData[0].Frequency
+		}
+		m.Channels = models.ModelChannelView(msg, currentFreq)
+		m.PopupState = 9
+
+		m.Overlay = updateOverlayModel(m, &m.Channels)
+		return m, nil
+
+	case common.WirelessLoadedMsg:
+		address, permAddress := "", ""
+		if len(m.DeviceData) > 0 {
+			address, permAddress = m.DeviceData[0].Address, m.DeviceData[0].PermAddress
+		}
+		m.Wireless = models.ModelWirelessEditor(msg.Name, msg.Config, address, permAddress)
+		m.PopupState = 8
+
+		m.Overlay = updateOverlayModel(m, &m.Wireless)
+		return m, m.Wireless.Init()
+
+	case common.WifiQRMsg:
+		m.QRView = models.ModelQRView(msg.SSID, msg.Payload)
+		m.PopupState = 3
+
+		m.Overlay = updateOverlayModel(m, &m.QRView)
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
 		m.Height = msg.Height
@@ -332,9 +813,7 @@ func (m NetpalaData) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		// case "e":
 		case "ctrl+c", "ctrl+q", "q", "ctrl+w":
-			m.Conn.RemoveSignal(m.DBusSignals)
-			m.Conn.Close()
-			return m, tea.Quit
+			return m.quit()
 
 	case "r":
 		var cmds []tea.Cmd
@@ -345,6 +824,36 @@ func (m NetpalaData) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		return m, tea.Batch(cmds...)
 
+		case "h":
+			if len(m.DeviceData) == 0 {
+				return m, nil
+			}
+			// Run it on the adapter selected in the device table
+			wifiDevice := m.DeviceData[0]
+			if m.selectedBox == 0 && m.SelectedEntry < len(m.DeviceData) {
+				wifiDevice = m.DeviceData[m.SelectedEntry]
+			}
+			if !network.SupportsAP(wifiDevice) {
+				return m, func() tea.Msg {
+					return common.NoticeMsg(fmt.Sprintf("%s does not support access point mode", wifiDevice.Name))
+				}
+			}
+
+			m.Hotspot = models.ModelHotspotForm()
+			m.Hotspot.Device = wifiDevice
+			m.Hotspot.Running = wifiDevice.Mode == "ap"
+			if cfg, ok := network.GetHotspotConfig(m.Conn); ok {
+				m.Hotspot.SetConfig(cfg)
+			}
+			m.PopupState = 2
+
+			m.Overlay = updateOverlayModel(m, &m.Hotspot)
+			if m.HotspotPolling {
+				return m, m.Hotspot.Init()
+			}
+			m.HotspotPolling = true
+			return m, tea.Batch(m.Hotspot.Init(), dbus.HotspotClientsCmd(wifiDevice.Name))
+
 		case "up", "k":
 			if m.SelectedEntry > 0 && !m.IsTyping {
 				m.SelectedEntry--
@@ -375,41 +884,150 @@ func (m NetpalaData) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter", " ":
 			if m.selectedBox == 0 && len(m.DeviceData) > 0 {
 				// Enable/Disable Wifi Card
-				return m, dbus.ToggleWifiCmd(m.Conn, !m.DeviceData[0].Powered)
+				if m.DeviceData[0].HardBlocked {
+					return m, func() tea.Msg { return common.NoticeMsg(common.HardBlockHint) }
+				}
+				return m, dbus.ToggleWifiCmd(m.Conn, m.DeviceData[0].SoftBlocked)
 			} else if m.selectedBox == 2 && len(m.VpnData) > 0 && len(m.DeviceData) > 0 {
 				// Toggle VPN
 				selectedVpn := m.VpnData[m.SelectedEntry]
-				return m, dbus.ToggleVpnCmd(m.Conn, selectedVpn.Path, selectedVpn.ActivePath, !selectedVpn.Connected)
+				toggleCmd := dbus.ToggleVpnCmd(m.Conn, selectedVpn.Path, selectedVpn.ActivePath, !selectedVpn.Connected)
+				if selectedVpn.Connected {
+					// Dropping a VPN can cut off a session that runs through it
+					return m, m.changeProfile(selectedVpn.Path, fmt.Sprintf("disconnected '%s'", selectedVpn.Name), toggleCmd)
+				}
+				return m, toggleCmd
 			} else if m.selectedBox == 3 && len(m.KnownNetworks) > 0 && len(m.DeviceData) > 0 {
 				// Connect to known network
 				selectedNetwork := m.KnownNetworks[m.SelectedEntry]
 				wifiDevice := m.DeviceData[0]
-				return m, dbus.ConnectToNetworkCmd(m.Conn, selectedNetwork.Path, wifiDevice.Path)
+				return m, m.switchConnection(wifiDevice, "switched networks", dbus.ConnectToNetworkCmd(m.Conn, selectedNetwork.Path, wifiDevice.Path))
 			} else if m.selectedBox == 4 && len(m.ScannedNetworks) > 0 && len(m.DeviceData) > 0 {
 				// Store the selected network before entering typing mode
 				m.SelectedNetwork = m.ScannedNetworks[m.SelectedEntry]
+				return m.joinNetwork()
+			}
+		case "m":
+			return m, dbus.ToggleAirplaneModeCmd(m.Conn, m.AirplaneRestore)
+		case "H":
+			return m, dbus.LoadHistoryCmd()
+		case "u", "ctrl+z":
+			if len(m.UndoStack) == 0 {
+				return m, func() tea.Msg { return common.NoticeMsg("Nothing to undo") }
+			}
+			snapshot := m.UndoStack[len(m.UndoStack)-1]
+			m.UndoStack = m.UndoStack[:len(m.UndoStack)-1]
+			return m, dbus.RestoreProfileCmd(m.Conn, snapshot)
+		case "x":
+			return m, dbus.CheckConnectivityCmd(m.Conn)
+		case "t":
+			if !m.Connectivity.CheckAvailable {
+				return m, func() tea.Msg { return common.NoticeMsg("No connectivity check URI is configured in NetworkManager") }
+			}
+			return m, dbus.SetConnectivityCheckCmd(m.Conn, !m.Connectivity.CheckEnabled)
+		case "l":
+			if m.Connectivity.Connectivity == common.ConnectivityPortal && m.Connectivity.CheckURI != "" {
+				// The probe URL is redirected to the portal's login page
+				return m, dbus.OpenURLCmd(m.Connectivity.CheckURI)
+			}
+		case "c":
+			// Chart channel congestion from the last scan
+			return m, dbus.LoadChannelsCmd(m.Conn)
+		case "o":
+			if m.selectedBox == 3 && len(m.KnownNetworks) > 0 {
+				// Edit the advanced Wi-Fi settings of a known network
+				m.SelectedNetwork = common.ScannedNetwork{
+					Path: m.KnownNetworks[m.SelectedEntry].Path,
+					SSID: m.KnownNetworks[m.SelectedEntry].SSID,
+				}
+				return m, dbus.LoadWirelessCmd(m.Conn, m.SelectedNetwork.Path)
+			}
+		case "b":
+			// List the access points (BSSIDs) of a network
+			if m.selectedBox == 3 && len(m.KnownNetworks) > 0 {
+				known := m.KnownNetworks[m.SelectedEntry]
+				m.SelectedNetwork = common.ScannedNetwork{
+					Path:     known.Path,
+					SSID:     known.SSID,
+					Security: known.Security,
+				}
+				return m, dbus.LoadAccessPointsCmd(m.Conn, known.SSID)
+			} else if m.selectedBox == 4 && len(m.ScannedNetworks) > 0 {
+				m.SelectedNetwork = m.ScannedNetworks[m.SelectedEntry]
+				return m, dbus.LoadAccessPointsCmd(m.Conn, m.SelectedNetwork.SSID)
+			}
+		case "i":
+			// Import a connection profile from a file
+			m.IsTyping = true
+			m.InputAction = "import"
+			m.StatusBar.Input.Placeholder = "Path to .nmconnection, wg-quick .conf or .ovpn file..."
+			m.StatusBar.Input.Focus()
+			return m, nil
+		case "e":
+			var exportPath godbus.ObjectPath
+			if m.selectedBox == 2 && len(m.VpnData) > 0 {
+				exportPath = m.VpnData[m.SelectedEntry].Path
+			} else if m.selectedBox == 3 && len(m.KnownNetworks) > 0 {
+				exportPath = m.KnownNetworks[m.SelectedEntry].Path
+			} else {
+				return m, nil
+			}
+			return m, dbus.ExportConnectionCmd(m.Conn, exportPath, filepath.Join(common.DataDir(), "exports"))
+		case "n":
+			if m.selectedBox == 2 && len(m.VpnData) > 0 {
+				// Rename the VPN profile
+				m.SelectedNetwork = common.ScannedNetwork{
+					Path: m.VpnData[m.SelectedEntry].Path,
+					SSID: m.VpnData[m.SelectedEntry].Name,
+				}
+				m.IsTyping = true
+				m.InputAction = "rename"
+				m.StatusBar.Input.Placeholder = fmt.Sprintf("New name for '%s'...", m.SelectedNetwork.SSID)
+				m.StatusBar.Input.SetValue(m.SelectedNetwork.SSID)
+				m.StatusBar.Input.CursorEnd()
+				m.StatusBar.Input.Focus()
+				return m, nil
+			}
+		case "a":
+			if m.selectedBox == 2 && len(m.VpnData) > 0 {
+				// Toggle autoconnect for the VPN profile
+				selectedVpn := m.VpnData[m.SelectedEntry]
+				return m, m.changeProfile(selectedVpn.Path, fmt.Sprintf("changed autoconnect of '%s'", selectedVpn.Name),
+					dbus.SetAutoconnectCmd(m.Conn, selectedVpn.Path, !selectedVpn.AutoConnect))
+			}
+		case "w":
+			if m.selectedBox == 2 && len(m.VpnData) > 0 && m.VpnData[m.SelectedEntry].ConnType == "WireGuard" {
+				// Edit the WireGuard profile
+				return m, dbus.LoadWireGuardCmd(m.Conn, m.VpnData[m.SelectedEntry].Path)
+			}
+		case "s":
+			if m.selectedBox == 3 && len(m.KnownNetworks) > 0 {
+				// Share known network as a QR code
+				return m, dbus.ShowWifiQRCmd(m.Conn, m.KnownNetworks[m.SelectedEntry].Path)
+			}
+		case "v":
+			if m.selectedBox == 3 && len(m.KnownNetworks) > 0 {
+				// Choose VPNs to start along with this network
+				selected := m.KnownNetworks[m.SelectedEntry]
+				m.SelectedNetwork = common.ScannedNetwork{Path: selected.Path, SSID: selected.SSID}
+				m.VpnPicker = models.ModelVpnPicker(selected.SSID, m.VpnData, selected.Secondaries)
+				m.PopupState = 6
 
-				switch m.SelectedNetwork.Security {
-				case "wpa2-eap":
-					m.Form.SSIDSelected = m.SelectedNetwork.SSID
-					m.PopupState = 0
-
-					m.Overlay = updateOverlayModel(m, &m.Form)
-					return m, nil
-				case "open":
-					// Open network, connect directly
-					wifiDevice := m.DeviceData[0]
-					return m, dbus.AddAndConnectToNetworkCmd(m.Conn, m.SelectedNetwork, "", wifiDevice.Path)
-				case "owe":
-					// Opportunistically encrypted network, connect directly
-					wifiDevice := m.DeviceData[0]
-					return m, dbus.AddAndConnectToNetworkCmd(m.Conn, m.SelectedNetwork, "", wifiDevice.Path)
-				default:
-					// Most common case: prompt for password
-					m.IsTyping = true
-					m.StatusBar.Input.Placeholder = "Enter Wi-Fi Password..."
-					m.StatusBar.Input.Focus()
+				m.Overlay = updateOverlayModel(m, &m.VpnPicker)
+				return m, nil
+			}
+		case "p":
+			if m.selectedBox == 3 && len(m.KnownNetworks) > 0 {
+				// Reveal the saved password, after confirmation
+				m.SelectedNetwork = common.ScannedNetwork{
+					Path: m.KnownNetworks[m.SelectedEntry].Path,
+					SSID: m.KnownNetworks[m.SelectedEntry].SSID,
 				}
+				m.PopupState = 1
+				m.ConfirmAction = "reveal"
+				m.Confirmation.Message = fmt.Sprintf("Show the saved password for '%s'?\n", m.SelectedNetwork.SSID)
+
+				m.Overlay = updateOverlayModel(m, &m.Confirmation)
 				return m, nil
 			}
 		case "delete":
@@ -423,8 +1041,21 @@ func (m NetpalaData) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					Signal: m.KnownNetworks[m.SelectedEntry].Signal,
 				}
 				m.PopupState = 1
+				m.ConfirmAction = "delete"
 				m.Confirmation.Message = fmt.Sprintf("Are you sure you want to delete the known network '%s'?\n", m.SelectedNetwork.SSID)
 
+				m.Overlay = updateOverlayModel(m, &m.Confirmation)
+				return m, nil
+			} else if !m.IsTyping && m.selectedBox == 2 && len(m.VpnData) > 0 {
+				// Delete VPN profile
+				m.SelectedNetwork = common.ScannedNetwork{
+					Path: m.VpnData[m.SelectedEntry].Path,
+					SSID: m.VpnData[m.SelectedEntry].Name,
+				}
+				m.PopupState = 1
+				m.ConfirmAction = "delete"
+				m.Confirmation.Message = fmt.Sprintf("Are you sure you want to delete the VPN '%s'?\n", m.SelectedNetwork.SSID)
+
 				m.Overlay = updateOverlayModel(m, &m.Confirmation)
 				return m, nil
 			}
@@ -450,6 +1081,9 @@ func (m NetpalaData) View() string {
 	m.Tables.VpnData = m.VpnData
 	m.Tables.KnownNetworks = m.KnownNetworks
 	m.Tables.ScannedNetworks = m.ScannedNetworks
+	m.Tables.SignalHistory = m.SignalHistory
+	m.Tables.Throughput = m.Throughput
+	m.Tables.Connectivity = m.Connectivity
 
 	switch m.PopupState {
 	case 0:
@@ -458,12 +1092,46 @@ func (m NetpalaData) View() string {
 	case 1:
 		m.Overlay = updateOverlayModel(m, &m.Confirmation)
 		return m.Overlay.View() + m.StatusBar.View()
+	case 2:
+		m.Overlay = updateOverlayModel(m, &m.Hotspot)
+		return m.Overlay.View() + m.StatusBar.View()
+	case 3:
+		m.Overlay = updateOverlayModel(m, &m.QRView)
+		return m.Overlay.View() + m.StatusBar.View()
+	case 4:
+		m.Overlay = updateOverlayModel(m, &m.SecretView)
+		return m.Overlay.View() + m.StatusBar.View()
+	case 5:
+		m.Overlay = updateOverlayModel(m, &m.WireGuard)
+		return m.Overlay.View() + m.StatusBar.View()
+	case 6:
+		m.Overlay = updateOverlayModel(m, &m.VpnPicker)
+		return m.Overlay.View() + m.StatusBar.View()
+	case 7:
+		m.Overlay = updateOverlayModel(m, &m.APList)
+		return m.Overlay.View() + m.StatusBar.View()
+	case 8:
+		m.Overlay = updateOverlayModel(m, &m.Wireless)
+		return m.Overlay.View() + m.StatusBar.View()
+	case 9:
+		m.Overlay = updateOverlayModel(m, &m.Channels)
+		return m.Overlay.View() + m.StatusBar.View()
+	case 10:
+		m.Overlay = updateOverlayModel(m, &m.Checkpoint)
+		return m.Overlay.View() + m.StatusBar.View()
+	case 11:
+		m.Overlay = updateOverlayModel(m, &m.History)
+		return m.Overlay.View() + m.StatusBar.View()
 	default:
 		return m.Tables.View() + m.StatusBar.View()
 	}
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCLI(os.Args[1:]))
+	}
+
 	p := tea.NewProgram(NetpalaModel(), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		os.Exit(1)
@@ -471,6 +1139,149 @@ func main() {
 	}
 }
 
+// recordDeviceState writes connects, disconnects and failed attempts of the
+// Wi-Fi device to the connection history.
+func (m NetpalaData) recordDeviceState(msg common.DeviceStateMsg) (NetpalaData, tea.Cmd) {
+	now := time.Now()
+	seen := common.HistoryEntry{Time: now, SSID: msg.SSID, BSSID: msg.BSSID}
+	if msg.Frequency > 0 {
+		seen.Band = common.FreqToBand(msg.Frequency)
+	}
+	device := ""
+	for _, d := range m.DeviceData {
+		if d.Path == msg.Path {
+			device = d.Name
+		}
+	}
+
+	switch {
+	case msg.New == common.DeviceStateActivated && msg.Old != common.DeviceStateActivated:
+		if seen.SSID == "" {
+			seen = m.Attempt
+			seen.Time = now
+		}
+		seen.Event = "connected"
+		m.Session, m.Attempt = seen, common.HistoryEntry{}
+		hook := dbus.RunHooksCmd(common.HookEvent{
+			Name: "connected", SSID: seen.SSID, BSSID: seen.BSSID, Device: device, IP: msg.IP,
+		})
+		if seen.SSID == "" {
+			return m, hook
+		}
+		return m, tea.Batch(dbus.RecordHistoryCmd(seen), hook)
+
+	case msg.Old == common.DeviceStateActivated:
+		entry := m.Session
+		if entry.SSID == "" {
+			// Connected before netpala started, so the length is unknown
+			entry = seen
+		} else {
+			entry.Duration = int64(now.Sub(m.Session.Time).Seconds())
+		}
+		entry.Time, entry.Event, entry.Reason = now, "disconnected", common.DeviceStateReason(msg.Reason)
+		m.Session = common.HistoryEntry{}
+		hook := dbus.RunHooksCmd(common.HookEvent{
+			Name: "disconnected", SSID: entry.SSID, BSSID: entry.BSSID, Device: device, Reason: entry.Reason,
+		})
+		if entry.SSID == "" {
+			return m, hook
+		}
+		return m, tea.Batch(dbus.RecordHistoryCmd(entry), hook)
+
+	case msg.New == common.DeviceStateFailed:
+		entry := m.Attempt
+		entry.Time, entry.Event, entry.Reason = now, "failed", common.DeviceStateReason(msg.Reason)
+		m.Attempt = common.HistoryEntry{}
+		hook := dbus.RunHooksCmd(common.HookEvent{
+			Name: "activation-failed", SSID: entry.SSID, BSSID: entry.BSSID, Device: device, Reason: entry.Reason,
+		})
+		if entry.SSID == "" {
+			return m, hook
+		}
+		return m, tea.Batch(dbus.RecordHistoryCmd(entry), hook)
+
+	case seen.SSID != "":
+		// Still connecting; remember where to in case it fails
+		m.Attempt = seen
+	}
+	return m, nil
+}
+
+// quit leaves the device's statistics as we found them and closes the bus
+// connection. Every exit key goes through here.
+func (m NetpalaData) quit() (tea.Model, tea.Cmd) {
+	if m.Conn == nil {
+		// Never got onto the bus
+		return m, tea.Quit
+	}
+	if m.Throughput.Path != "" {
+		network.SetStatisticsRefreshRate(m.Conn, m.Throughput.Path, m.StatsRestoreMs)
+	}
+	m.Conn.RemoveSignal(m.DBusSignals)
+	m.Conn.Close()
+	return m, tea.Quit
+}
+
+// switchConnection wraps activating or stopping a connection in a checkpoint
+// when the device is already connected, since leaving that network can cut
+// us off.
+func (m NetpalaData) switchConnection(device common.Device, description string, cmd tea.Cmd) tea.Cmd {
+	if device.State != 1 {
+		return cmd
+	}
+	return dbus.WithCheckpointCmd(m.Conn, description, cmd)
+}
+
+// changeProfile wraps a change to a saved profile in a checkpoint when the
+// profile is in use. A checkpoint only restores what is active on devices;
+// deleted inactive profiles come back through undo instead.
+func (m NetpalaData) changeProfile(path godbus.ObjectPath, description string, cmd tea.Cmd) tea.Cmd {
+	for _, k := range m.KnownNetworks {
+		if k.Path == path && k.Connected {
+			return dbus.WithCheckpointCmd(m.Conn, description, cmd)
+		}
+	}
+	for _, v := range m.VpnData {
+		if v.Path == path && v.ActivePath != "" {
+			return dbus.WithCheckpointCmd(m.Conn, description, cmd)
+		}
+	}
+	return cmd
+}
+
+// joinNetwork connects to m.SelectedNetwork, which has no saved profile yet,
+// asking for whatever credentials its security needs first.
+func (m NetpalaData) joinNetwork() (NetpalaData, tea.Cmd) {
+	switch sec := m.SelectedNetwork.Security; {
+	case sec.IsEnterprise():
+		m.Form.SSIDSelected = m.SelectedNetwork.SSID
+		if preset, ok := common.FindEapPreset(m.EapPresets, m.SelectedNetwork.SSID); ok {
+			m.Form.ApplyPreset(preset)
+		}
+		m.PopupState = 0
+	
+		m.Overlay = updateOverlayModel(m, &m.Form)
+		return m, nil
+	case !sec.NeedsPassword():
+		// Open or opportunistically encrypted network, connect directly
+		wifiDevice := m.DeviceData[0]
+		return m, dbus.AddAndConnectToNetworkCmd(m.Conn, m.SelectedNetwork, "", wifiDevice.Path)
+	case sec.IsWEP():
+		m.IsTyping = true
+		m.InputAction = "password"
+		m.StatusBar.Input.Placeholder = "WEP key (10/26 hex, 5/13 chars or passphrase)..."
+		m.StatusBar.Warning = "WEP is broken, anyone nearby can recover the key"
+		m.StatusBar.Input.Focus()
+	default:
+		// Most common case: prompt for password
+		m.IsTyping = true
+		m.InputAction = "password"
+		m.StatusBar.Input.Placeholder = "Enter Wi-Fi Password..."
+		m.StatusBar.Input.Focus()
+	}
+	return m, nil
+}
+
 func updateOverlayModel(m NetpalaData, popup tea.Model) overlay.Model {
 	newOverlay := overlay.Model{
 		Background: &m.Tables,
@@ -0,0 +1,82 @@
+package network
+
+import (
+	"netpala/common"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// readAccessPoint reads the properties of one AccessPoint object.
+func readAccessPoint(c *dbus.Conn, path dbus.ObjectPath) common.AccessPoint {
+	props := GetProps(c.Object(NMDest, path), AccessPointIF)
+
+	ap := common.AccessPoint{Path: path}
+	if ssid, ok := props["Ssid"].Value().([]byte); ok {
+		ap.SSID = strings.TrimRight(string(ssid), "\x00")
+	}
+	ap.BSSID, _ = props["HwAddress"].Value().(string)
+	if freq, ok := props["Frequency"].Value().(uint32); ok {
+		ap.Frequency = int(freq)
+	}
+	ap.Bandwidth, _ = props["Bandwidth"].Value().(uint32)
+	ap.MaxBitrate, _ = props["MaxBitrate"].Value().(uint32)
+	if strength, ok := props["Strength"].Value().(byte); ok {
+		ap.Signal = int(strength)
+	}
+	if lastSeen, ok := props["LastSeen"].Value().(int32); ok && lastSeen >= 0 {
+		// LastSeen counts CLOCK_BOOTTIME seconds, the same clock as sysinfo's uptime
+		var info syscall.Sysinfo_t
+		if syscall.Sysinfo(&info) == nil {
+			ap.LastSeen = time.Now().Add(-time.Duration(int64(info.Uptime)-int64(lastSeen)) * time.Second)
+		}
+	}
+
+	flags, _ := props["Flags"].Value().(uint32)
+	wpaFlags, _ := props["WpaFlags"].Value().(uint32)
+	rsnFlags, _ := props["RsnFlags"].Value().(uint32)
+	ap.Security = common.SecurityFromFlags(flags, wpaFlags, rsnFlags)
+	return ap
+}
+
+// GetAllAccessPoints lists every access point in range, hidden networks
+// included, strongest first.
+func GetAllAccessPoints(c *dbus.Conn) []common.AccessPoint {
+	var aps []common.AccessPoint
+	for _, devPath := range wifiDevices(c) {
+		var apPaths []dbus.ObjectPath
+		if err := c.Object(NMDest, devPath).Call(WifiIF+".GetAllAccessPoints", 0).Store(&apPaths); err != nil {
+			continue
+		}
+		for _, apPath := range apPaths {
+			aps = append(aps, readAccessPoint(c, apPath))
+		}
+	}
+	sort.SliceStable(aps, func(i, j int) bool { return aps[i].Signal > aps[j].Signal })
+	return aps
+}
+
+// GetAccessPoints lists every access point broadcasting ssid, strongest first.
+func GetAccessPoints(c *dbus.Conn, ssid string) []common.AccessPoint {
+	var aps []common.AccessPoint
+	for _, ap := range GetAllAccessPoints(c) {
+		if ap.SSID == ssid {
+			aps = append(aps, ap)
+		}
+	}
+	return aps
+}
+
+func wifiDevices(c *dbus.Conn) []dbus.ObjectPath {
+	var devPaths, wifi []dbus.ObjectPath
+	c.Object(NMDest, dbus.ObjectPath(NMPath)).Call(NMDest+".GetDevices", 0).Store(&devPaths)
+	for _, devPath := range devPaths {
+		if t, ok := GetProps(c.Object(NMDest, devPath), DevIF)["DeviceType"].Value().(uint32); ok && t == 2 {
+			wifi = append(wifi, devPath)
+		}
+	}
+	return wifi
+}
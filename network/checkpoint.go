@@ -0,0 +1,49 @@
+package network
+
+import (
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// NM_CHECKPOINT_CREATE_FLAG_DELETE_NEW_CONNECTIONS: a rollback also removes
+// profiles added after the checkpoint.
+const checkpointDeleteNewConnections uint32 = 0x02
+
+// CreateCheckpoint snapshots the state of every device. NetworkManager
+// rolls back on its own after timeoutSecs unless the checkpoint is
+// destroyed first, which keeps a change that cut us off from being
+// permanent.
+func CreateCheckpoint(c *dbus.Conn, timeoutSecs uint32) (dbus.ObjectPath, error) {
+	var path dbus.ObjectPath
+	err := c.Object(NMDest, NMPath).Call(NMDest+".CheckpointCreate", 0,
+		[]dbus.ObjectPath{}, timeoutSecs, checkpointDeleteNewConnections).Store(&path)
+	if err != nil {
+		return "", fmt.Errorf("failed to create checkpoint: %w", err)
+	}
+	return path, nil
+}
+
+// DestroyCheckpoint keeps the changes made since the checkpoint.
+func DestroyCheckpoint(c *dbus.Conn, path dbus.ObjectPath) error {
+	if call := c.Object(NMDest, NMPath).Call(NMDest+".CheckpointDestroy", 0, path); call.Err != nil {
+		return fmt.Errorf("failed to destroy checkpoint: %w", call.Err)
+	}
+	return nil
+}
+
+// RollbackCheckpoint undoes the changes made since the checkpoint and
+// returns the devices that couldn't be restored.
+func RollbackCheckpoint(c *dbus.Conn, path dbus.ObjectPath) ([]dbus.ObjectPath, error) {
+	var results map[string]uint32
+	if err := c.Object(NMDest, NMPath).Call(NMDest+".CheckpointRollback", 0, path).Store(&results); err != nil {
+		return nil, fmt.Errorf("failed to roll back checkpoint: %w", err)
+	}
+	var failed []dbus.ObjectPath
+	for dev, result := range results {
+		if result != 0 { // NM_ROLLBACK_RESULT_OK
+			failed = append(failed, dbus.ObjectPath(dev))
+		}
+	}
+	return failed, nil
+}
@@ -0,0 +1,39 @@
+package network
+
+import (
+	"fmt"
+	"netpala/common"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// GetConnectivity reads the connectivity state from NetworkManager.
+func GetConnectivity(c *dbus.Conn) common.ConnectivityStatus {
+	p := GetProps(c.Object(NMDest, NMPath), NMDest)
+	var status common.ConnectivityStatus
+	status.State, _ = p["State"].Value().(uint32)
+	status.Connectivity, _ = p["Connectivity"].Value().(uint32)
+	status.CheckAvailable, _ = p["ConnectivityCheckAvailable"].Value().(bool)
+	status.CheckEnabled, _ = p["ConnectivityCheckEnabled"].Value().(bool)
+	status.CheckURI, _ = p["ConnectivityCheckUri"].Value().(string)
+	return status
+}
+
+// CheckConnectivity asks NetworkManager to probe the connectivity check URI
+// now rather than at its next interval.
+func CheckConnectivity(c *dbus.Conn) error {
+	var result uint32
+	if err := c.Object(NMDest, NMPath).Call(NMDest+".CheckConnectivity", 0).Store(&result); err != nil {
+		return fmt.Errorf("failed to check connectivity: %w", err)
+	}
+	return nil
+}
+
+// SetConnectivityCheck turns NetworkManager's periodic connectivity checks
+// on or off.
+func SetConnectivityCheck(c *dbus.Conn, enabled bool) error {
+	if err := c.Object(NMDest, NMPath).SetProperty(NMDest+".ConnectivityCheckEnabled", dbus.MakeVariant(enabled)); err != nil {
+		return fmt.Errorf("failed to set ConnectivityCheckEnabled: %w", err)
+	}
+	return nil
+}
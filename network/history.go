@@ -0,0 +1,166 @@
+package network
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"netpala/common"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Once a log file grows past this it's cut back to the newest half.
+const logMaxBytes = 1 << 20
+
+// HistoryFile holds the connection history, one JSON object per line.
+func HistoryFile() string {
+	return filepath.Join(common.StateDir(), "history.jsonl")
+}
+
+// AppendHistory adds an entry to the history file.
+func AppendHistory(entry common.HistoryEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := appendLog(HistoryFile(), append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// appendLog appends data to a file under the state directory, trimming it
+// when it gets too big.
+func appendLog(file string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(file); err == nil && info.Size() > logMaxBytes {
+		return trimLog(file)
+	}
+	return nil
+}
+
+// trimLog drops the older half of a line based file.
+func trimLog(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	kept := strings.Join(lines[len(lines)/2:], "")
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, []byte(kept), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// LoadHistory reads the history file, oldest entry first. Lines that can't
+// be parsed are skipped and reported in the error.
+func LoadHistory() ([]common.HistoryEntry, error) {
+	f, err := os.Open(HistoryFile())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []common.HistoryEntry
+	bad := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e common.HistoryEntry
+		if json.Unmarshal(scanner.Bytes(), &e) != nil {
+			bad++
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("failed to read history: %w", err)
+	}
+	if bad > 0 {
+		return entries, fmt.Errorf("skipped %d unreadable history entries", bad)
+	}
+	return entries, nil
+}
+
+// GetIPv4Address returns the first address of a device or active
+// connection, which both have an Ip4Config property on iface.
+func GetIPv4Address(c *dbus.Conn, path dbus.ObjectPath, iface string) string {
+	v, err := c.Object(NMDest, path).GetProperty(iface + ".Ip4Config")
+	if err != nil {
+		return ""
+	}
+	ip4Path, _ := v.Value().(dbus.ObjectPath)
+	if ip4Path == "" || ip4Path == "/" {
+		return ""
+	}
+	ip4 := GetProps(c.Object(NMDest, ip4Path), "org.freedesktop.NetworkManager.IP4Config")
+	addrs, _ := ip4["AddressData"].Value().([]map[string]dbus.Variant)
+	if len(addrs) == 0 {
+		return ""
+	}
+	addr, _ := addrs[0]["address"].Value().(string)
+	return addr
+}
+
+// GetActiveNetwork returns the SSID, BSSID and frequency a Wi-Fi device is
+// using, or connecting with. The SSID falls back to the profile name when no
+// access point is associated yet.
+func GetActiveNetwork(c *dbus.Conn, devicePath dbus.ObjectPath) (ssid, bssid string, frequency int) {
+	obj := c.Object(NMDest, devicePath)
+	if v, err := obj.GetProperty(WifiIF + ".ActiveAccessPoint"); err == nil {
+		if ap, _ := v.Value().(dbus.ObjectPath); ap != "" && ap != "/" {
+			p := GetProps(c.Object(NMDest, ap), AccessPointIF)
+			if raw, ok := p["Ssid"].Value().([]byte); ok {
+				ssid = string(raw)
+			}
+			bssid, _ = p["HwAddress"].Value().(string)
+			freq, _ := p["Frequency"].Value().(uint32)
+			frequency = int(freq)
+		}
+	}
+	if ssid == "" {
+		ssid = GetActiveConnectionID(c, devicePath)
+	}
+	return ssid, strings.ToUpper(bssid), frequency
+}
+
+// GetActiveConnectionID returns the name of the profile active on a device,
+// or "" once it is gone.
+func GetActiveConnectionID(c *dbus.Conn, devicePath dbus.ObjectPath) string {
+	v, err := c.Object(NMDest, devicePath).GetProperty(DevIF + ".ActiveConnection")
+	if err != nil {
+		return ""
+	}
+	ac, _ := v.Value().(dbus.ObjectPath)
+	if ac == "" || ac == "/" {
+		return ""
+	}
+	id, err := c.Object(NMDest, ac).GetProperty("org.freedesktop.NetworkManager.Connection.Active.Id")
+	if err != nil {
+		return ""
+	}
+	name, _ := id.Value().(string)
+	return name
+}
@@ -0,0 +1,144 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"netpala/common"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// How long a hook may run before it's killed.
+const HookTimeout = 10 * time.Second
+
+// HooksDir holds one directory per event (see common.HookEvents) with the
+// executables to run for it, in name order.
+func HooksDir() string {
+	return filepath.Join(common.ConfigDir(), "hooks")
+}
+
+// HooksLog records every hook run and its output.
+func HooksLog() string {
+	return filepath.Join(common.StateDir(), "hooks.log")
+}
+
+// hookScripts lists the executables of an event, skipping hidden files and
+// editor backups.
+func hookScripts(event string) []string {
+	entries, err := os.ReadDir(filepath.Join(HooksDir(), event))
+	if err != nil {
+		return nil
+	}
+	var scripts []string
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+			continue
+		}
+		path := filepath.Join(HooksDir(), event, name)
+		if info, err := os.Stat(path); err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+			continue
+		}
+		scripts = append(scripts, path)
+	}
+	sort.Strings(scripts)
+	return scripts
+}
+
+type hookJob struct {
+	event  common.HookEvent
+	result chan error
+}
+
+// Events are queued and run by a single worker, so the scripts of one event
+// finish before those of the next start.
+var (
+	hookMu     sync.Mutex
+	hookJobs   []hookJob
+	hookWake   = make(chan struct{}, 1)
+	hookWorker sync.Once
+)
+
+// QueueHooks schedules the scripts of an event after those of every event
+// queued before it. The channel receives the result of RunHooks.
+func QueueHooks(event common.HookEvent) <-chan error {
+	job := hookJob{event: event, result: make(chan error, 1)}
+	hookMu.Lock()
+	hookJobs = append(hookJobs, job)
+	hookMu.Unlock()
+
+	hookWorker.Do(func() { go runHookQueue() })
+	select {
+	case hookWake <- struct{}{}:
+	default:
+		// The worker is already awake and will see the job
+	}
+	return job.result
+}
+
+func runHookQueue() {
+	for range hookWake {
+		for {
+			hookMu.Lock()
+			if len(hookJobs) == 0 {
+				hookMu.Unlock()
+				break
+			}
+			job := hookJobs[0]
+			hookJobs = hookJobs[1:]
+			hookMu.Unlock()
+			job.result <- RunHooks(job.event)
+		}
+	}
+}
+
+// RunHooks runs the scripts of an event one after another with the event in
+// their environment. Failures are returned together once all have run.
+func RunHooks(event common.HookEvent) error {
+	var errs []error
+	for _, script := range hookScripts(event.Name) {
+		if err := runHook(script, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(script), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func runHook(script string, event common.HookEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), HookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, script)
+	cmd.Env = append(os.Environ(), event.Env()...)
+	// Don't wait forever on children that keep the output open
+	cmd.WaitDelay = time.Second
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", HookTimeout)
+	}
+
+	status := "ok"
+	if err != nil {
+		status = err.Error()
+	}
+	var log bytes.Buffer
+	fmt.Fprintf(&log, "%s %s %s (%s): %s\n", start.Format(time.RFC3339), event.Name, script,
+		time.Since(start).Round(time.Millisecond), status)
+	for _, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+		if line != "" {
+			fmt.Fprintf(&log, "  %s\n", line)
+		}
+	}
+	if lerr := appendLog(HooksLog(), log.Bytes()); lerr != nil && err == nil {
+		err = fmt.Errorf("failed to write %s: %w", HooksLog(), lerr)
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+package network
+
+import (
+	"bufio"
+	"fmt"
+	"netpala/common"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// NM_WIFI_DEVICE_CAP_* flags from the WirelessCapabilities property.
+const (
+	WifiCapAP       = 0x00000040
+	WifiCapFreq2GHz = 0x00000200
+	WifiCapFreq5GHz = 0x00000400
+)
+
+// HotspotID is the connection id netpala uses for the profile it manages.
+const HotspotID = "Hotspot"
+
+// SupportsAP reports whether the adapter can run in access point mode.
+func SupportsAP(dev common.Device) bool {
+	return dev.Capabilities&WifiCapAP != 0
+}
+
+// FindHotspotConnection returns the saved access point profile created by
+// netpala, or "/" if there isn't one yet.
+func FindHotspotConnection(c *dbus.Conn) (dbus.ObjectPath, map[string]map[string]dbus.Variant) {
+	settingsObj := c.Object(NMDest, "/org/freedesktop/NetworkManager/Settings")
+	var connPaths []dbus.ObjectPath
+	if err := settingsObj.Call("org.freedesktop.NetworkManager.Settings.ListConnections", 0).Store(&connPaths); err != nil {
+		return "/", nil
+	}
+
+	for _, path := range connPaths {
+		var settings map[string]map[string]dbus.Variant
+		if c.Object(NMDest, path).Call("org.freedesktop.NetworkManager.Settings.Connection.GetSettings", 0).Store(&settings) != nil {
+			continue
+		}
+		id, _ := settings["connection"]["id"].Value().(string)
+		mode, _ := settings["802-11-wireless"]["mode"].Value().(string)
+		if id == HotspotID && mode == "ap" {
+			return path, settings
+		}
+	}
+	return "/", nil
+}
+
+// GetHotspotClients reads the dnsmasq lease file NetworkManager keeps for
+// shared connections on the given interface.
+func GetHotspotClients(iface string) ([]common.HotspotClient, error) {
+	file, err := os.Open(fmt.Sprintf("/var/lib/NetworkManager/dnsmasq-%s.leases", iface))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	// Each line looks like: <expiry> <mac> <ip> <hostname> <client-id>
+	var clients []common.HotspotClient
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		expires, _ := strconv.ParseInt(fields[0], 10, 64)
+		hostname := fields[3]
+		if hostname == "*" {
+			hostname = "-"
+		}
+		clients = append(clients, common.HotspotClient{
+			MAC:      strings.ToLower(fields[1]),
+			IP:       fields[2],
+			Hostname: hostname,
+			Expires:  expires,
+		})
+	}
+	return clients, scanner.Err()
+}
+
+// GetHotspotConfig pre-fills the hotspot form from the saved profile. Secrets
+// are not returned by GetSettings, so the password is left empty.
+func GetHotspotConfig(c *dbus.Conn) (common.HotspotConfig, bool) {
+	path, settings := FindHotspotConnection(c)
+	if path == "/" {
+		return common.HotspotConfig{}, false
+	}
+
+	cfg := common.HotspotConfig{Band: "bg", Security: "wpa2-psk"}
+	if ssid, ok := settings["802-11-wireless"]["ssid"].Value().([]byte); ok {
+		cfg.SSID = strings.TrimRight(string(ssid), "\x00")
+	}
+	if band, ok := settings["802-11-wireless"]["band"].Value().(string); ok && band != "" {
+		cfg.Band = band
+	}
+	if channel, ok := settings["802-11-wireless"]["channel"].Value().(uint32); ok {
+		cfg.Channel = channel
+	}
+	if km, ok := settings["802-11-wireless-security"]["key-mgmt"].Value().(string); ok && km == "sae" {
+		cfg.Security = "wpa3-sae"
+	}
+	return cfg, true
+}
@@ -0,0 +1,691 @@
+package network
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Conversion between NetworkManager's keyfile format (.nmconnection) and the
+// settings map used on D-Bus. Only keys whose D-Bus type isn't a plain string
+// need to be listed here.
+
+type keyKind int
+
+const (
+	kindString keyKind = iota
+	kindBool
+	kindUint32
+	kindUint64
+	kindInt32
+	kindInt64
+	kindStrings
+	kindBytes
+	kindMAC
+	kindCert
+)
+
+var keyfileSections = map[string]string{
+	"wifi":          "802-11-wireless",
+	"wifi-security": "802-11-wireless-security",
+	"ethernet":      "802-3-ethernet",
+}
+
+var keyfileKinds = map[string]keyKind{
+	"autoconnect":               kindBool,
+	"hidden":                    kindBool,
+	"never-default":             kindBool,
+	"ignore-auto-dns":           kindBool,
+	"ignore-auto-routes":        kindBool,
+	"may-fail":                  kindBool,
+	"system-ca-certs":           kindBool,
+	"persistent":                kindBool,
+	"peer-routes":               kindBool,
+	"read-only":                 kindBool,
+	"dhcp-send-hostname":        kindBool,
+	"auto-negotiate":            kindBool,
+	"browser-only":              kindBool,
+	"channel":                   kindUint32,
+	"mtu":                       kindUint32,
+	"wep-tx-keyidx":             kindUint32,
+	"wep-key-type":              kindUint32,
+	"listen-port":               kindUint32,
+	"fwmark":                    kindUint32,
+	"persistent-keepalive":      kindUint32,
+	"powersave":                 kindUint32,
+	"wake-on-wlan":              kindUint32,
+	"mac-address-randomization": kindUint32,
+	"tx-power":                  kindUint32,
+	"rate":                      kindUint32,
+	"route-table":               kindUint32,
+	"gateway-ping-timeout":      kindUint32,
+	"timeout":                   kindUint32,
+	"speed":                     kindUint32,
+	"wake-on-lan":               kindUint32,
+	"wps-method":                kindUint32,
+	"timestamp":                 kindUint64,
+	"autoconnect-priority":      kindInt32,
+	"autoconnect-retries":       kindInt32,
+	"pmf":                       kindInt32,
+	"ip6-privacy":               kindInt32,
+	"addr-gen-mode":             kindInt32,
+	"auth-retries":              kindInt32,
+	"metered":                   kindInt32,
+	"multi-connect":             kindInt32,
+	"dns-priority":              kindInt32,
+	"dhcp-timeout":              kindInt32,
+	"mdns":                      kindInt32,
+	"llmnr":                     kindInt32,
+	"dns-over-tls":              kindInt32,
+	"dhcp-send-hostname-v2":     kindInt32,
+	"dhcp-send-release":         kindInt32,
+	"ra-timeout":                kindInt32,
+	"dad-timeout":               kindInt32,
+	"required-timeout":          kindInt32,
+	"auth-timeout":              kindInt32,
+	"wait-device-timeout":       kindInt32,
+	"wait-activation-delay":     kindInt32,
+	"link-local":                kindInt32,
+	"lldp":                      kindInt32,
+	"autoconnect-slaves":        kindInt32,
+	"autoconnect-ports":         kindInt32,
+	"ip4-auto-default-route":    kindInt32,
+	"ip6-auto-default-route":    kindInt32,
+	"ap-isolation":              kindInt32,
+	"fils":                      kindInt32,
+	"route-metric":              kindInt64,
+	"eap":                       kindStrings,
+	"proto":                     kindStrings,
+	"pairwise":                  kindStrings,
+	"group":                     kindStrings,
+	"dns-search":                kindStrings,
+	"dns-options":               kindStrings,
+	"secondaries":               kindStrings,
+	"permissions":               kindStrings,
+	"altsubject-matches":        kindStrings,
+	"phase2-altsubject-matches": kindStrings,
+	"allowed-ips":               kindStrings,
+	"seen-bssids":               kindStrings,
+	"mac-address-blacklist":     kindStrings,
+	"mac-address-denylist":      kindStrings,
+	"ssid":                      kindBytes,
+	"mac-address":               kindMAC,
+	"bssid":                     kindMAC,
+	"ca-cert":                   kindCert,
+	"client-cert":               kindCert,
+	"private-key":               kindCert,
+	"phase2-ca-cert":            kindCert,
+	"phase2-client-cert":        kindCert,
+	"phase2-private-key":        kindCert,
+}
+
+var addrGenModes = map[string]int32{"eui64": 0, "stable-privacy": 1, "default-or-eui64": 2, "default": 3}
+
+// kindOf looks up the type of a key. Certificate and key blobs only exist in
+// 802-1x; elsewhere (like wireguard's private-key) the same names are strings.
+func kindOf(setting, key string) keyKind {
+	if k, ok := keyfileKinds[key]; ok {
+		if k == kindCert && setting != "802-1x" {
+			return kindString
+		}
+		return k
+	}
+	if strings.HasSuffix(key, "-flags") {
+		return kindUint32
+	}
+	return kindString
+}
+
+// unescapeKeyfile undoes GKeyFile value escaping.
+func unescapeKeyfile(s string) string {
+	return strings.NewReplacer(`\s`, " ", `\n`, "\n", `\t`, "\t", `\r`, "\r", `\\`, `\`).Replace(s)
+}
+
+func escapeKeyfile(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\t", `\t`, "\r", `\r`).Replace(s)
+	if strings.HasPrefix(s, " ") {
+		s = `\s` + s[1:]
+	}
+	return s
+}
+
+// splitKeyfileList splits a ';'-separated list, honouring "\;" escapes.
+func splitKeyfileList(s string) []string {
+	var items []string
+	var cur strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == ';':
+			cur.WriteByte(';')
+			i++
+		case s[i] == ';':
+			items = append(items, unescapeKeyfile(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteByte(s[i])
+		}
+	}
+	if cur.Len() > 0 {
+		items = append(items, unescapeKeyfile(cur.String()))
+	}
+	return items
+}
+
+// ParseKeyfile converts a .nmconnection file into D-Bus connection settings.
+func ParseKeyfile(data []byte) (map[string]map[string]dbus.Variant, error) {
+	settings := map[string]map[string]dbus.Variant{}
+	raw := map[string]map[string]string{}
+	var order []string
+
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if _, ok := raw[section]; !ok {
+				raw[section] = map[string]string{}
+				order = append(order, section)
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || section == "" {
+			return nil, fmt.Errorf("line %d: expected key=value inside a [section]", lineNo)
+		}
+		raw[section][strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if _, ok := raw["connection"]; !ok {
+		return nil, fmt.Errorf("keyfile has no [connection] section")
+	}
+
+	for _, name := range order {
+		values := raw[name]
+		switch {
+		case name == "vpn":
+			vpn := settings["vpn"]
+			if vpn == nil {
+				vpn = map[string]dbus.Variant{}
+			}
+			vpnData := map[string]string{}
+			for k, v := range values {
+				switch k {
+				case "service-type", "user-name":
+					vpn[k] = dbus.MakeVariant(unescapeKeyfile(v))
+				case "persistent":
+					vpn[k] = dbus.MakeVariant(v == "true")
+				case "timeout":
+					n, _ := strconv.ParseUint(v, 10, 32)
+					vpn[k] = dbus.MakeVariant(uint32(n))
+				default:
+					vpnData[k] = unescapeKeyfile(v)
+				}
+			}
+			vpn["data"] = dbus.MakeVariant(vpnData)
+			settings["vpn"] = vpn
+			continue
+		case name == "vpn-secrets":
+			vpn := settings["vpn"]
+			if vpn == nil {
+				vpn = map[string]dbus.Variant{}
+			}
+			secrets := map[string]string{}
+			for k, v := range values {
+				secrets[k] = unescapeKeyfile(v)
+			}
+			vpn["secrets"] = dbus.MakeVariant(secrets)
+			settings["vpn"] = vpn
+			continue
+		case strings.HasPrefix(name, "wireguard-peer."):
+			peer := map[string]dbus.Variant{"public-key": dbus.MakeVariant(strings.TrimPrefix(name, "wireguard-peer."))}
+			for k, v := range values {
+				typed, err := keyfileValue("wireguard", k, v)
+				if err != nil {
+					return nil, fmt.Errorf("[%s] %s: %w", name, k, err)
+				}
+				peer[k] = typed
+			}
+			wg := settings["wireguard"]
+			if wg == nil {
+				wg = map[string]dbus.Variant{}
+			}
+			peers, _ := wg["peers"].Value().([]map[string]dbus.Variant)
+			wg["peers"] = dbus.MakeVariant(append(peers, peer))
+			settings["wireguard"] = wg
+			continue
+		}
+
+		setting := name
+		if alias, ok := keyfileSections[name]; ok {
+			setting = alias
+		}
+		out := settings[setting]
+		if out == nil {
+			out = map[string]dbus.Variant{}
+		}
+
+		var addresses, routes []map[string]dbus.Variant
+		for _, k := range sortedKeys(values) {
+			v := values[k]
+			switch {
+			case (setting == "ipv4" || setting == "ipv6") && (strings.HasPrefix(k, "address") && k != "address-data"):
+				// addressN=ip/prefix[,gateway]
+				addr, gw, _ := strings.Cut(strings.TrimSuffix(v, ";"), ",")
+				ip, ipNet, err := net.ParseCIDR(addr)
+				if err != nil {
+					return nil, fmt.Errorf("[%s] %s: %w", name, k, err)
+				}
+				prefix, _ := ipNet.Mask.Size()
+				addresses = append(addresses, map[string]dbus.Variant{
+					"address": dbus.MakeVariant(ip.String()),
+					"prefix":  dbus.MakeVariant(uint32(prefix)),
+				})
+				if gw != "" {
+					out["gateway"] = dbus.MakeVariant(gw)
+				}
+			case (setting == "ipv4" || setting == "ipv6") && strings.HasPrefix(k, "route") && !strings.Contains(k, "-") && !strings.Contains(k, "_"):
+				// routeN=dest/prefix[,next-hop[,metric]]
+				parts := strings.Split(strings.TrimSuffix(v, ";"), ",")
+				_, ipNet, err := net.ParseCIDR(parts[0])
+				if err != nil {
+					return nil, fmt.Errorf("[%s] %s: %w", name, k, err)
+				}
+				prefix, _ := ipNet.Mask.Size()
+				route := map[string]dbus.Variant{
+					"dest":   dbus.MakeVariant(ipNet.IP.String()),
+					"prefix": dbus.MakeVariant(uint32(prefix)),
+				}
+				if len(parts) > 1 && parts[1] != "" {
+					route["next-hop"] = dbus.MakeVariant(parts[1])
+				}
+				if len(parts) > 2 {
+					metric, _ := strconv.ParseUint(parts[2], 10, 32)
+					route["metric"] = dbus.MakeVariant(uint32(metric))
+				}
+				routes = append(routes, route)
+			case (setting == "ipv4" || setting == "ipv6") && k == "dns":
+				dns, err := parseDNS(setting, splitKeyfileList(v))
+				if err != nil {
+					return nil, fmt.Errorf("[%s] %s: %w", name, k, err)
+				}
+				out["dns"] = dns
+			case setting == "ipv6" && k == "addr-gen-mode":
+				if mode, ok := addrGenModes[v]; ok {
+					out[k] = dbus.MakeVariant(mode)
+				} else if typed, err := keyfileValue(setting, k, v); err == nil {
+					out[k] = typed
+				} else {
+					return nil, fmt.Errorf("[%s] %s: %w", name, k, err)
+				}
+			case setting == "proxy" && k == "method":
+				// Unlike the ip methods, the proxy method is an enum.
+				n, err := strconv.ParseInt(v, 10, 32)
+				if err != nil {
+					return nil, fmt.Errorf("[%s] %s: %w", name, k, err)
+				}
+				out[k] = dbus.MakeVariant(int32(n))
+			case setting == "connection" && k == "type":
+				t := unescapeKeyfile(v)
+				if alias, ok := keyfileSections[t]; ok {
+					t = alias
+				}
+				out[k] = dbus.MakeVariant(t)
+			case setting == "802-11-wireless" && k == "cloned-mac-address":
+				// D-Bus exposes the string form (random, stable, ...) separately.
+				out["assigned-mac-address"] = dbus.MakeVariant(v)
+			default:
+				typed, err := keyfileValue(setting, k, v)
+				if err != nil {
+					return nil, fmt.Errorf("[%s] %s: %w", name, k, err)
+				}
+				out[k] = typed
+			}
+		}
+		if len(addresses) > 0 {
+			out["address-data"] = dbus.MakeVariant(addresses)
+		}
+		if len(routes) > 0 {
+			out["route-data"] = dbus.MakeVariant(routes)
+		}
+		settings[setting] = out
+	}
+
+	return settings, nil
+}
+
+func keyfileValue(setting, key, value string) (dbus.Variant, error) {
+	switch kindOf(setting, key) {
+	case kindBool:
+		b, err := strconv.ParseBool(value)
+		return dbus.MakeVariant(b), err
+	case kindUint32:
+		n, err := strconv.ParseUint(value, 10, 32)
+		return dbus.MakeVariant(uint32(n)), err
+	case kindUint64:
+		n, err := strconv.ParseUint(value, 10, 64)
+		return dbus.MakeVariant(n), err
+	case kindInt32:
+		n, err := strconv.ParseInt(value, 10, 32)
+		return dbus.MakeVariant(int32(n)), err
+	case kindInt64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		return dbus.MakeVariant(n), err
+	case kindStrings:
+		items := splitKeyfileList(value)
+		if key == "eap" {
+			// NetworkManager only accepts lowercase method names.
+			for i := range items {
+				items[i] = strings.ToLower(items[i])
+			}
+		}
+		return dbus.MakeVariant(items), nil
+	case kindBytes:
+		// Either a plain string or a legacy list of decimal bytes ("72;105;").
+		if strings.HasSuffix(value, ";") {
+			var out []byte
+			for _, part := range strings.Split(strings.TrimSuffix(value, ";"), ";") {
+				n, err := strconv.ParseUint(part, 10, 8)
+				if err != nil {
+					return dbus.MakeVariant([]byte(unescapeKeyfile(value))), nil
+				}
+				out = append(out, byte(n))
+			}
+			return dbus.MakeVariant(out), nil
+		}
+		return dbus.MakeVariant([]byte(unescapeKeyfile(value))), nil
+	case kindMAC:
+		mac, err := net.ParseMAC(value)
+		return dbus.MakeVariant([]byte(mac)), err
+	case kindCert:
+		return dbus.MakeVariant(CertValue(unescapeKeyfile(value))), nil
+	default:
+		return dbus.MakeVariant(unescapeKeyfile(value)), nil
+	}
+}
+
+// CertValue turns a certificate path into the NUL-terminated file:// URI
+// NetworkManager expects for certificate and key properties.
+func CertValue(path string) []byte {
+	path = strings.TrimPrefix(path, "file://")
+	return append([]byte("file://"+path), 0)
+}
+
+func parseDNS(setting string, servers []string) (dbus.Variant, error) {
+	if setting == "ipv4" {
+		var out []uint32
+		for _, s := range servers {
+			ip := net.ParseIP(s).To4()
+			if ip == nil {
+				return dbus.Variant{}, fmt.Errorf("invalid IPv4 address '%s'", s)
+			}
+			out = append(out, binary.LittleEndian.Uint32(ip))
+		}
+		return dbus.MakeVariant(out), nil
+	}
+	var out [][]byte
+	for _, s := range servers {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return dbus.Variant{}, fmt.Errorf("invalid IPv6 address '%s'", s)
+		}
+		out = append(out, []byte(ip.To16()))
+	}
+	return dbus.MakeVariant(out), nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FormatKeyfile renders D-Bus connection settings as a .nmconnection file.
+func FormatKeyfile(settings map[string]map[string]dbus.Variant) string {
+	reverse := map[string]string{}
+	for k, v := range keyfileSections {
+		reverse[v] = k
+	}
+
+	var sections []string
+	for _, name := range sortedKeys(settings) {
+		if name != "connection" && name != "ipv4" && name != "ipv6" && name != "proxy" {
+			sections = append(sections, name)
+		}
+	}
+	sections = append([]string{"connection"}, sections...)
+	sections = append(sections, "ipv4", "ipv6", "proxy")
+
+	var sb strings.Builder
+	var extra []string // sections generated from nested values, written at the end
+	for _, setting := range sections {
+		values, ok := settings[setting]
+		if !ok {
+			continue
+		}
+		name := setting
+		if alias, ok := reverse[setting]; ok {
+			name = alias
+		}
+		fmt.Fprintf(&sb, "[%s]\n", name)
+
+		for _, key := range sortedKeys(values) {
+			value := values[key].Value()
+			switch {
+			case setting == "connection" && key == "type":
+				t, _ := value.(string)
+				if alias, ok := reverse[t]; ok {
+					t = alias
+				}
+				fmt.Fprintf(&sb, "type=%s\n", t)
+			case key == "address-data":
+				addrs, _ := value.([]map[string]dbus.Variant)
+				for i, a := range addrs {
+					line := fmt.Sprintf("address%d=%v/%v", i+1, a["address"].Value(), a["prefix"].Value())
+					if gw, ok := values["gateway"].Value().(string); ok && i == 0 && gw != "" {
+						line += "," + gw
+					}
+					sb.WriteString(line + "\n")
+				}
+			case key == "route-data":
+				routes, _ := value.([]map[string]dbus.Variant)
+				for i, r := range routes {
+					line := fmt.Sprintf("route%d=%v/%v", i+1, r["dest"].Value(), r["prefix"].Value())
+					if hop, ok := r["next-hop"].Value().(string); ok {
+						line += "," + hop
+						if metric, ok := r["metric"].Value().(uint32); ok {
+							line += fmt.Sprintf(",%d", metric)
+						}
+					}
+					sb.WriteString(line + "\n")
+				}
+			case key == "gateway" && values["address-data"].Value() != nil,
+				key == "addresses", key == "routes", key == "dns-data", key == "cloned-mac-address":
+				// Covered by address-data/dns, or legacy duplicates of other keys.
+			case setting == "ipv6" && key == "addr-gen-mode":
+				mode, _ := value.(int32)
+				for name, m := range addrGenModes {
+					if m == mode {
+						fmt.Fprintf(&sb, "addr-gen-mode=%s\n", name)
+					}
+				}
+			case key == "assigned-mac-address":
+				fmt.Fprintf(&sb, "cloned-mac-address=%v\n", value)
+			case key == "dns":
+				fmt.Fprintf(&sb, "dns=%s\n", formatDNS(value))
+			case setting == "vpn" && key == "data":
+				data, _ := value.(map[string]string)
+				for _, k := range sortedKeys(data) {
+					fmt.Fprintf(&sb, "%s=%s\n", k, escapeKeyfile(data[k]))
+				}
+			case setting == "vpn" && key == "secrets":
+				secrets, _ := value.(map[string]string)
+				if len(secrets) > 0 {
+					section := "\n[vpn-secrets]\n"
+					for _, k := range sortedKeys(secrets) {
+						section += fmt.Sprintf("%s=%s\n", k, escapeKeyfile(secrets[k]))
+					}
+					extra = append(extra, section)
+				}
+			case setting == "wireguard" && key == "peers":
+				peers, _ := value.([]map[string]dbus.Variant)
+				for _, peer := range peers {
+					section := fmt.Sprintf("\n[wireguard-peer.%v]\n", peer["public-key"].Value())
+					for _, k := range sortedKeys(peer) {
+						if k != "public-key" {
+							section += fmt.Sprintf("%s=%s\n", k, formatKeyfileValue(setting, k, peer[k].Value()))
+						}
+					}
+					extra = append(extra, section)
+				}
+			default:
+				formatted := formatKeyfileValue(setting, key, value)
+				if _, isString := value.(string); formatted != "" || isString {
+					fmt.Fprintf(&sb, "%s=%s\n", key, formatted)
+				}
+			}
+		}
+		sb.WriteString("\n")
+	}
+	for _, section := range extra {
+		sb.WriteString(strings.TrimPrefix(section, "\n") + "\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func formatKeyfileValue(setting, key string, value any) string {
+	switch v := value.(type) {
+	case string:
+		return escapeKeyfile(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case []string:
+		if len(v) == 0 {
+			return ""
+		}
+		var parts []string
+		for _, s := range v {
+			parts = append(parts, strings.ReplaceAll(escapeKeyfile(s), ";", `\;`))
+		}
+		return strings.Join(parts, ";") + ";"
+	case []byte:
+		switch kindOf(setting, key) {
+		case kindMAC:
+			return strings.ToUpper(net.HardwareAddr(v).String())
+		case kindCert:
+			return strings.TrimPrefix(strings.TrimRight(string(v), "\x00"), "file://")
+		}
+		printable := true
+		for _, b := range v {
+			if b < 0x20 || b > 0x7e || b == ';' {
+				printable = false
+				break
+			}
+		}
+		if printable {
+			return escapeKeyfile(string(v))
+		}
+		var parts []string
+		for _, b := range v {
+			parts = append(parts, strconv.Itoa(int(b)))
+		}
+		return strings.Join(parts, ";") + ";"
+	case uint32, int32, int64, uint64, uint8:
+		return fmt.Sprintf("%d", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func formatDNS(value any) string {
+	var parts []string
+	switch v := value.(type) {
+	case []uint32:
+		for _, n := range v {
+			ip := make(net.IP, 4)
+			binary.LittleEndian.PutUint32(ip, n)
+			parts = append(parts, ip.String())
+		}
+	case [][]byte:
+		for _, b := range v {
+			parts = append(parts, net.IP(b).String())
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, ";") + ";"
+}
+
+// ExportKeyfile fetches a saved connection, including its secrets, and
+// renders it in keyfile format. It returns the connection id as well.
+func ExportKeyfile(c *dbus.Conn, path dbus.ObjectPath) (string, string, error) {
+	settings, err := GetSettingsWithSecrets(c, path)
+	if err != nil {
+		return "", "", err
+	}
+	// The last-used time belongs to this machine, not to the profile.
+	delete(settings["connection"], "timestamp")
+	id, _ := settings["connection"]["id"].Value().(string)
+	return id, FormatKeyfile(settings), nil
+}
+
+// GetSettingsWithSecrets reads a saved connection with whatever secrets the
+// agent is willing to hand out.
+func GetSettingsWithSecrets(c *dbus.Conn, path dbus.ObjectPath) (map[string]map[string]dbus.Variant, error) {
+	var settings map[string]map[string]dbus.Variant
+	if err := c.Object(NMDest, path).Call("org.freedesktop.NetworkManager.Settings.Connection.GetSettings", 0).Store(&settings); err != nil {
+		return nil, fmt.Errorf("failed to read connection: %w", err)
+	}
+
+	// Secrets are not part of GetSettings; merge in whatever the agent gives us.
+	for _, setting := range []string{"802-11-wireless-security", "802-1x", "vpn", "wireguard"} {
+		if _, ok := settings[setting]; !ok {
+			continue
+		}
+		secrets, err := GetSecrets(c, path, setting)
+		if err != nil {
+			continue
+		}
+		for k, v := range secrets[setting] {
+			if setting == "wireguard" && k == "peers" {
+				mergePeerSecrets(settings[setting], v)
+				continue
+			}
+			settings[setting][k] = v
+		}
+	}
+	return settings, nil
+}
+
+// mergePeerSecrets copies preshared keys returned by GetSecrets into the
+// matching peers of the wireguard setting.
+func mergePeerSecrets(wg map[string]dbus.Variant, secretPeers dbus.Variant) {
+	peers, _ := wg["peers"].Value().([]map[string]dbus.Variant)
+	withSecrets, _ := secretPeers.Value().([]map[string]dbus.Variant)
+	for _, sp := range withSecrets {
+		for _, p := range peers {
+			if p["public-key"].Value() == sp["public-key"].Value() {
+				for k, v := range sp {
+					p[k] = v
+				}
+			}
+		}
+	}
+	wg["peers"] = dbus.MakeVariant(peers)
+}
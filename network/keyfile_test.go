@@ -0,0 +1,201 @@
+package network
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// settingsFixture mirrors what GetSettings returns for a WPA-PSK profile
+// with a static IPv4 address.
+func settingsFixture() map[string]map[string]dbus.Variant {
+	return map[string]map[string]dbus.Variant{
+		"connection": {
+			"id":                   dbus.MakeVariant("Home"),
+			"uuid":                 dbus.MakeVariant("8d6ea2f5-3c4b-4c0e-9a8e-1f2b3c4d5e6f"),
+			"type":                 dbus.MakeVariant("802-11-wireless"),
+			"interface-name":       dbus.MakeVariant("wlan0"),
+			"autoconnect":          dbus.MakeVariant(false),
+			"autoconnect-priority": dbus.MakeVariant(int32(-5)),
+			"auth-retries":         dbus.MakeVariant(int32(3)),
+			"metered":              dbus.MakeVariant(int32(2)),
+			"timestamp":            dbus.MakeVariant(uint64(1760000000)),
+			"wait-device-timeout":  dbus.MakeVariant(int32(5000)),
+			"permissions":          dbus.MakeVariant([]string{"user:alice:"}),
+		},
+		"802-11-wireless": {
+			"ssid":                 dbus.MakeVariant([]byte("Home Net")),
+			"mode":                 dbus.MakeVariant("infrastructure"),
+			"band":                 dbus.MakeVariant("a"),
+			"channel":              dbus.MakeVariant(uint32(36)),
+			"hidden":               dbus.MakeVariant(true),
+			"bssid":                dbus.MakeVariant([]byte{0x02, 0x11, 0x22, 0x33, 0x44, 0x55}),
+			"mac-address":          dbus.MakeVariant([]byte{0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF}),
+			"assigned-mac-address": dbus.MakeVariant("stable"),
+			"powersave":            dbus.MakeVariant(uint32(2)),
+			"seen-bssids":          dbus.MakeVariant([]string{"02:11:22:33:44:55"}),
+		},
+		"802-11-wireless-security": {
+			"key-mgmt":  dbus.MakeVariant("wpa-psk"),
+			"psk":       dbus.MakeVariant("correct horse"),
+			"psk-flags": dbus.MakeVariant(uint32(0)),
+			"pmf":       dbus.MakeVariant(int32(2)),
+			"proto":     dbus.MakeVariant([]string{"rsn"}),
+			"pairwise":  dbus.MakeVariant([]string{"ccmp"}),
+		},
+		"ipv4": {
+			"method": dbus.MakeVariant("manual"),
+			"address-data": dbus.MakeVariant([]map[string]dbus.Variant{{
+				"address": dbus.MakeVariant("192.168.1.20"),
+				"prefix":  dbus.MakeVariant(uint32(24)),
+			}}),
+			"gateway": dbus.MakeVariant("192.168.1.1"),
+			"route-data": dbus.MakeVariant([]map[string]dbus.Variant{{
+				"dest":     dbus.MakeVariant("10.0.0.0"),
+				"prefix":   dbus.MakeVariant(uint32(8)),
+				"next-hop": dbus.MakeVariant("192.168.1.254"),
+				"metric":   dbus.MakeVariant(uint32(50)),
+			}}),
+			"dns":                dbus.MakeVariant([]uint32{0x01010101, 0x09090909}),
+			"dns-search":         dbus.MakeVariant([]string{"lan"}),
+			"dhcp-send-hostname": dbus.MakeVariant(false),
+			"dhcp-timeout":       dbus.MakeVariant(int32(45)),
+			"may-fail":           dbus.MakeVariant(false),
+			"never-default":      dbus.MakeVariant(true),
+			"route-metric":       dbus.MakeVariant(int64(600)),
+			"route-table":        dbus.MakeVariant(uint32(0)),
+		},
+		"ipv6": {
+			"method":        dbus.MakeVariant("auto"),
+			"addr-gen-mode": dbus.MakeVariant(int32(1)),
+			"ip6-privacy":   dbus.MakeVariant(int32(0)),
+			"ra-timeout":    dbus.MakeVariant(int32(0)),
+			"dns":           dbus.MakeVariant([][]byte{{0x26, 0x06, 0x47, 0x00, 0x47, 0x00, 0, 0, 0, 0, 0, 0, 0, 0, 0x11, 0x11}}),
+		},
+		"proxy": {
+			"method":       dbus.MakeVariant(int32(0)),
+			"browser-only": dbus.MakeVariant(false),
+		},
+	}
+}
+
+// wireGuardFixture is a WireGuard profile with two peers, one of them with a
+// preshared key.
+func wireGuardFixture() map[string]map[string]dbus.Variant {
+	return map[string]map[string]dbus.Variant{
+		"connection": {
+			"id":             dbus.MakeVariant("wg0"),
+			"uuid":           dbus.MakeVariant("4b0a5a8e-5b1f-4d4e-8a43-0b2c9d1e7f60"),
+			"type":           dbus.MakeVariant("wireguard"),
+			"interface-name": dbus.MakeVariant("wg0"),
+			"autoconnect":    dbus.MakeVariant(false),
+		},
+		"wireguard": {
+			"private-key":            dbus.MakeVariant("yAnz5TF+lXXJte14tji3zlMNq+hd2rYUIgJBgB3fBmk="),
+			"private-key-flags":      dbus.MakeVariant(uint32(0)),
+			"listen-port":            dbus.MakeVariant(uint32(51820)),
+			"fwmark":                 dbus.MakeVariant(uint32(51820)),
+			"mtu":                    dbus.MakeVariant(uint32(1420)),
+			"peer-routes":            dbus.MakeVariant(true),
+			"ip4-auto-default-route": dbus.MakeVariant(int32(-1)),
+			"peers": dbus.MakeVariant([]map[string]dbus.Variant{
+				{
+					"public-key":           dbus.MakeVariant("xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg="),
+					"endpoint":             dbus.MakeVariant("vpn.example.com:51820"),
+					"allowed-ips":          dbus.MakeVariant([]string{"0.0.0.0/0", "::/0"}),
+					"persistent-keepalive": dbus.MakeVariant(uint32(25)),
+					"preshared-key":        dbus.MakeVariant("FpCyhws9cxwWoV4xELtfJvjJN+zQVRPISllRWgeopVE="),
+					"preshared-key-flags":  dbus.MakeVariant(uint32(0)),
+				},
+				{
+					"public-key":           dbus.MakeVariant("TrMvSoP4jYQlY6RIzBgbssQqY3vxI2Pi+y71lOWWXX0="),
+					"allowed-ips":          dbus.MakeVariant([]string{"10.8.0.0/24"}),
+					"persistent-keepalive": dbus.MakeVariant(uint32(0)),
+				},
+			}),
+		},
+		"ipv4": {
+			"method": dbus.MakeVariant("manual"),
+			"address-data": dbus.MakeVariant([]map[string]dbus.Variant{{
+				"address": dbus.MakeVariant("10.8.0.2"),
+				"prefix":  dbus.MakeVariant(uint32(24)),
+			}}),
+		},
+		"ipv6": {
+			"method": dbus.MakeVariant("disabled"),
+		},
+	}
+}
+
+// vpnFixture is an OpenVPN plugin profile with a saved password.
+func vpnFixture() map[string]map[string]dbus.Variant {
+	return map[string]map[string]dbus.Variant{
+		"connection": {
+			"id":          dbus.MakeVariant("Office"),
+			"uuid":        dbus.MakeVariant("c5e7d1a2-9f3b-4e8c-a1d6-2b7f0e9c3a54"),
+			"type":        dbus.MakeVariant("vpn"),
+			"autoconnect": dbus.MakeVariant(false),
+		},
+		"vpn": {
+			"service-type": dbus.MakeVariant("org.freedesktop.NetworkManager.openvpn"),
+			"user-name":    dbus.MakeVariant("alice"),
+			"persistent":   dbus.MakeVariant(true),
+			"timeout":      dbus.MakeVariant(uint32(60)),
+			"data": dbus.MakeVariant(map[string]string{
+				"connection-type":  "password-tls",
+				"remote":           "vpn.example.com:1194, backup.example.com:443",
+				"ca":               "/home/alice/.local/share/netpala/openvpn/office/ca.pem",
+				"username":         "alice",
+				"password-flags":   "0",
+				"verify-x509-name": "subject:CN=vpn.example.com",
+			}),
+			"secrets": dbus.MakeVariant(map[string]string{
+				"password": "s3cret; with spaces",
+			}),
+		},
+		"ipv4": {
+			"method":        dbus.MakeVariant("auto"),
+			"never-default": dbus.MakeVariant(true),
+		},
+		"ipv6": {
+			"method": dbus.MakeVariant("auto"),
+		},
+	}
+}
+
+func TestKeyfileRoundTrip(t *testing.T) {
+	fixtures := map[string]func() map[string]map[string]dbus.Variant{
+		"wifi":      settingsFixture,
+		"wireguard": wireGuardFixture,
+		"vpn":       vpnFixture,
+	}
+	for name, fixture := range fixtures {
+		t.Run(name, func(t *testing.T) {
+			want := fixture()
+			parsed, err := ParseKeyfile([]byte(FormatKeyfile(fixture())))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for setting, values := range want {
+				got, ok := parsed[setting]
+				if !ok {
+					t.Errorf("setting %s missing after round trip", setting)
+					continue
+				}
+				for key, value := range values {
+					if !reflect.DeepEqual(got[key].Value(), value.Value()) {
+						t.Errorf("%s.%s = %#v (%T), want %#v (%T)", setting, key,
+							got[key].Value(), got[key].Value(), value.Value(), value.Value())
+					}
+				}
+				for key := range got {
+					if _, ok := values[key]; !ok {
+						t.Errorf("unexpected %s.%s after round trip", setting, key)
+					}
+				}
+			}
+		})
+	}
+}
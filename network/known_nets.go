@@ -1,6 +1,7 @@
 package network
 
 import (
+	"net"
 	"netpala/common"
 	"sort"
 	"strings"
@@ -75,12 +76,17 @@ func GetKnownNetworks(conn *dbus.Conn) []common.KnownNetwork {
 	_ = setObj.Call("org.freedesktop.NetworkManager.Settings.ListConnections", 0).Store(&conns)
 
 	var known []common.KnownNetwork
+	vpnNames := map[string]string{} // uuid -> id, to resolve secondaries
 	for _, c := range conns {
 		cobj := conn.Object(NMDest, c)
 		var s map[string]map[string]dbus.Variant
 		if cobj.Call("org.freedesktop.NetworkManager.Settings.Connection.GetSettings", 0).Store(&s) != nil {
 			continue
 		}
+		if t, _ := s["connection"]["type"].Value().(string); t == "vpn" || t == "wireguard" {
+			uuid, _ := s["connection"]["uuid"].Value().(string)
+			vpnNames[uuid], _ = s["connection"]["id"].Value().(string)
+		}
 		wcfg, ok := s["802-11-wireless"]
 		if !ok {
 			continue
@@ -100,37 +106,28 @@ func GetKnownNetworks(conn *dbus.Conn) []common.KnownNetwork {
 				auto = av.Value().(bool)
 			}
 		}
-		sec := "open" // Default for no security section
-    if wsec, ok := s["802-11-wireless-security"]; ok {
-      if km, ok := wsec["key-mgmt"]; ok {
-        kmStr := strings.ToLower(km.Value().(string))
-        switch {
-        case strings.Contains(kmStr, "sae"):
-          sec = "wpa3-sae"
-        case strings.Contains(kmStr, "owe"):
-          sec = "owe"
-        case strings.Contains(kmStr, "wpa-psk"):
-          sec = "wpa2-psk"
-        case strings.Contains(kmStr, "wpa-eap"):
-          sec = "wpa2-eap"
-        case strings.Contains(kmStr, "none"):
-          sec = "wep"
-        default:
-          sec = "encrypted"
-        }
-      } else {
-				// Security section exists but has no key-mgmt.
-				// Could be WEP or other.
-				sec = "encrypted"
-      }
-    }
+		keyMgmt, _ := s["802-11-wireless-security"]["key-mgmt"].Value().(string)
+		sec := common.SecurityFromKeyMgmt(keyMgmt)
+		secondaries, _ := s["connection"]["secondaries"].Value().([]string)
+		pinned := ""
+		if mac, ok := wcfg["bssid"].Value().([]byte); ok && len(mac) == 6 {
+			pinned = strings.ToUpper(net.HardwareAddr(mac).String())
+		}
 		apInfo := aps[ss]
 		known = append(known, common.KnownNetwork{
 
 			Path: c, SSID: ss, Security: sec, Connected: apInfo.Connected, Hidden: hidden,
 			AutoConnect: auto, Signal: apInfo.Signal, BSSID: apInfo.BSSID,
+			Secondaries: secondaries, PinnedBSSID: pinned,
 		})
 	}
+	for i := range known {
+		for _, uuid := range known[i].Secondaries {
+			if name, ok := vpnNames[uuid]; ok {
+				known[i].VPNs = append(known[i].VPNs, name)
+			}
+		}
+	}
 	sort.SliceStable(known, func(i, j int) bool {
 		if known[i].Connected != known[j].Connected {
 			return known[i].Connected
@@ -15,6 +15,8 @@ const (
 	DevIF         = "org.freedesktop.NetworkManager.Device"
 	WifiIF        = "org.freedesktop.NetworkManager.Device.Wireless"
 	AccessPointIF = "org.freedesktop.NetworkManager.AccessPoint"
+	VpnIF         = "org.freedesktop.NetworkManager.VPN.Connection"
+	DevStatsIF    = "org.freedesktop.NetworkManager.Device.Statistics"
 )
 
 func GetDevicesData(c *dbus.Conn) []common.Device {
@@ -26,33 +28,6 @@ func GetDevicesData(c *dbus.Conn) []common.Device {
 	var connPaths []dbus.ObjectPath
 	_ = settingsObj.Call("org.freedesktop.NetworkManager.Settings.ListConnections", 0).Store(&connPaths)
 
-	inferSecurity := func(sec map[string]dbus.Variant) string {
-    if sec == nil {
-      return "open"
-    }
-    if v, ok := sec["key-mgmt"]; ok {
-      km := strings.ToLower(v.Value().(string))
-      switch {
-      case strings.Contains(km, "sae"):
-        return "wpa3-sae"
-      case strings.Contains(km, "owe"):
-        return "owe"
-      case strings.Contains(km, "wpa-psk"):
-        return "wpa2-psk"
-      case strings.Contains(km, "wpa-eap"):
-        return "wpa2-eap"
-      case strings.Contains(km, "none"):
-        // "none" key-mgmt inside a security block typically means WEP.
-        return "wep" 
-      }
-    }
-    // Fallback if key-mgmt is missing but a security section exists
-    if _, ok := sec["psk"]; ok {
-      return "wpa-psk"
-    }
-    return "encrypted"
-  }
-
 	var devs []dbus.ObjectPath
 	nm.Call(NMDest+".GetDevices", 0).Store(&devs)
 
@@ -79,17 +54,30 @@ func GetDevicesData(c *dbus.Conn) []common.Device {
 		iface := dp["Interface"].Value().(string)
 		mac := strings.ToLower(dp["HwAddress"].Value().(string))
 		wp := GetProps(obj, WifiIF)
+		permMac, _ := wp["PermHwAddress"].Value().(string)
 		mode := wp["Mode"].Value().(uint32)
 		ap := wp["ActiveAccessPoint"].Value().(dbus.ObjectPath)
 
+		var capabilities uint32
+		if capsVar, ok := wp["WirelessCapabilities"]; ok {
+			capabilities, _ = capsVar.Value().(uint32)
+		}
+
 		var isScanning bool
 		if scanningVar, ok := wp["Scanning"]; ok {
 			isScanning, _ = scanningVar.Value().(bool)
 		}
 
-		bssid, frequency, security := "-", 0, "-"
+		bssid, frequency, security := "-", 0, common.Security{}
 		if ap != "/" {
 			apObj := c.Object(NMDest, ap)
+			// What the access point offers, narrowed down below to what the
+			// saved profile actually uses.
+			apProps := GetProps(apObj, AccessPointIF)
+			flags, _ := apProps["Flags"].Value().(uint32)
+			wpaFlags, _ := apProps["WpaFlags"].Value().(uint32)
+			rsnFlags, _ := apProps["RsnFlags"].Value().(uint32)
+			security = common.SecurityFromFlags(flags, wpaFlags, rsnFlags)
 			if bssidVar, err := apObj.GetProperty("org.freedesktop.NetworkManager.AccessPoint.HwAddress"); err == nil {
 				bssid = bssidVar.Value().(string)
 			}
@@ -110,7 +98,8 @@ func GetDevicesData(c *dbus.Conn) []common.Device {
 				if wcfg, ok := settings["802-11-wireless"]; ok {
 					if ssidV, ok := wcfg["ssid"]; ok {
 						if b, ok := ssidV.Value().([]byte); ok && strings.TrimRight(string(b), "\x00") == activeSSID {
-							security = inferSecurity(settings["802-11-wireless-security"])
+							keyMgmt, _ := settings["802-11-wireless-security"]["key-mgmt"].Value().(string)
+							security = common.SecurityFromKeyMgmt(keyMgmt)
 							break
 						}
 					}
@@ -122,15 +111,21 @@ func GetDevicesData(c *dbus.Conn) []common.Device {
 			modeStr = fmt.Sprintf("%d", mode)
 		}
 
+		softEnabled, _ := p["WirelessEnabled"].Value().(bool)
+		hardEnabled, _ := p["WirelessHardwareEnabled"].Value().(bool)
 		devicesList = append(devicesList, common.Device{
 			Path: d,
 			Name: iface, Mode: modeStr,
-			Powered:      p["WirelessEnabled"].Value().(bool) && p["WirelessHardwareEnabled"].Value().(bool),
+			Powered:      softEnabled && hardEnabled,
+			SoftBlocked:  !softEnabled,
+			HardBlocked:  !hardEnabled,
 			Address:      mac,
+			PermAddress:  strings.ToLower(permMac),
 			State:        deviceState, // **FIX:** Use the accurate per-device state.
 			CurrentBSSID: bssid,
 			Scanning:     isScanning,
 			Frequency:    frequency, Security: security,
+			Capabilities: capabilities,
 		})
 	}
 	return devicesList
@@ -40,7 +40,9 @@ func GetVpnData(c *dbus.Conn) []common.VpnConnection {
 		return nil
 	}
 
-	// 3. Iterate through saved connections and find the VPNs.
+	// 3. Iterate through saved connections and find the VPNs, remembering
+	// which Wi-Fi networks start them as secondaries.
+	wifiNames := make(map[string][]string) // VPN uuid -> Wi-Fi ids
 	for _, path := range savedConnPaths {
 		connObj := c.Object(NMDest, path)
 		var settings map[string]map[string]dbus.Variant
@@ -60,8 +62,18 @@ func GetVpnData(c *dbus.Conn) []common.VpnConnection {
 
 		connType, _ := connTypeVar.Value().(string)
 
+		if connType == "802-11-wireless" {
+			id, _ := connSettings["id"].Value().(string)
+			secondaries, _ := connSettings["secondaries"].Value().([]string)
+			for _, uuid := range secondaries {
+				wifiNames[uuid] = append(wifiNames[uuid], id)
+			}
+			continue
+		}
+
 		if connType == "wireguard" || connType == "vpn" {
 			name, _ := connSettings["id"].Value().(string)
+			uuid, _ := connSettings["uuid"].Value().(string)
 
 			friendlyType := "VPN"
 			if connType == "wireguard" {
@@ -74,18 +86,64 @@ func GetVpnData(c *dbus.Conn) []common.VpnConnection {
 				}
 			}
 
+			autoConnect := true // NetworkManager's default when unset
+			if v, ok := connSettings["autoconnect"].Value().(bool); ok {
+				autoConnect = v
+			}
+
 			// Check if this connection is active and get its active path.
 			activePath, isConnected := activeConnections[path]
 
-			vpnList = append(vpnList, common.VpnConnection{
-				Path:       path,
-				ActivePath: activePath, // Store the active path
-				Name:       name,
-				ConnType:   friendlyType,
-				Connected:  isConnected,
-			})
+			vpn := common.VpnConnection{
+				Path:        path,
+				ActivePath:  activePath, // Store the active path
+				UUID:        uuid,
+				Name:        name,
+				ConnType:    friendlyType,
+				Connected:   isConnected,
+				AutoConnect: autoConnect,
+			}
+			if isConnected {
+				getActiveVpnState(c, &vpn)
+			}
+			vpnList = append(vpnList, vpn)
 		}
 	}
+	for i := range vpnList {
+		vpnList[i].WifiNames = wifiNames[vpnList[i].UUID]
+	}
 
 	return vpnList
 }
+
+// getActiveVpnState fills in the live state, banner and addressing of an
+// active VPN connection.
+func getActiveVpnState(c *dbus.Conn, vpn *common.VpnConnection) {
+	acObj := c.Object(NMDest, vpn.ActivePath)
+	ap := GetProps(acObj, "org.freedesktop.NetworkManager.Connection.Active")
+	if ap == nil {
+		return
+	}
+
+	if isVpn, _ := ap["Vpn"].Value().(bool); isVpn {
+		vp := GetProps(acObj, VpnIF)
+		if state, ok := vp["VpnState"].Value().(uint32); ok {
+			vpn.State = common.VpnStateString(state)
+		}
+		vpn.Banner, _ = vp["Banner"].Value().(string)
+	} else if state, ok := ap["State"].Value().(uint32); ok {
+		vpn.State = common.ActiveStateString(state)
+	}
+
+	ip4Path, _ := ap["Ip4Config"].Value().(dbus.ObjectPath)
+	if ip4Path == "" || ip4Path == "/" {
+		return
+	}
+	ip4 := GetProps(c.Object(NMDest, ip4Path), "org.freedesktop.NetworkManager.IP4Config")
+	if addrs, ok := ip4["AddressData"].Value().([]map[string]dbus.Variant); ok && len(addrs) > 0 {
+		addr, _ := addrs[0]["address"].Value().(string)
+		prefix, _ := addrs[0]["prefix"].Value().(uint32)
+		vpn.IP = fmt.Sprintf("%s/%d", addr, prefix)
+	}
+	vpn.Gateway, _ = ip4["Gateway"].Value().(string)
+}
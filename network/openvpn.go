@@ -0,0 +1,350 @@
+package network
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/google/uuid"
+)
+
+const OpenVPNService = "org.freedesktop.NetworkManager.openvpn"
+
+// Directives that map 1:1 onto a NetworkManager-openvpn data key.
+var ovpnDataKeys = map[string]string{
+	"cipher":            "cipher",
+	"data-ciphers":      "data-ciphers",
+	"auth":              "auth",
+	"compress":          "compress",
+	"remote-cert-tls":   "remote-cert-tls",
+	"ns-cert-type":      "ns-cert-type",
+	"tun-mtu":           "tunnel-mtu",
+	"fragment":          "fragment-size",
+	"mssfix":            "mssfix",
+	"reneg-sec":         "reneg-seconds",
+	"ping":              "ping",
+	"ping-exit":         "ping-exit",
+	"ping-restart":      "ping-restart",
+	"tls-version-min":   "tls-version-min",
+	"tls-version-max":   "tls-version-max",
+	"tls-cipher":        "tls-cipher",
+	"keysize":           "keysize",
+	"max-routes":        "max-routes",
+	"connect-timeout":   "connect-timeout",
+	"tun-ipv6":          "tun-ipv6",
+	"mtu-disc":          "mtu-disc",
+	"allow-compression": "allow-compression",
+}
+
+// Directives with no NetworkManager equivalent that are safe to drop because
+// the plugin already behaves that way.
+var ovpnImplied = map[string]bool{
+	"client": true, "nobind": true, "persist-key": true, "persist-tun": true,
+	"resolv-retry": true, "verb": true, "mute": true, "pull": true, "tls-client": true,
+	"auth-nocache": true, "mute-replay-warnings": true, "redirect-gateway": true,
+	"setenv": true, "explicit-exit-notify": true, "block-outside-dns": true,
+	"auth-retry": true, "key-direction": true, "route-delay": true,
+}
+
+// Inline blocks and the data key their file path is stored under.
+var ovpnInline = map[string]string{
+	"ca":           "ca",
+	"cert":         "cert",
+	"key":          "key",
+	"tls-auth":     "ta",
+	"tls-crypt":    "tls-crypt",
+	"tls-crypt-v2": "tls-crypt-v2",
+	"secret":       "static-key",
+	"pkcs12":       "cert",
+}
+
+// ParseOvpn converts an OpenVPN client config into a NetworkManager "vpn"
+// connection. Relative file references are resolved against baseDir, inline
+// certificates and keys are written to certDir/<uuid of the new profile>, and
+// directives that can't be represented are returned as warnings.
+func ParseOvpn(name string, data []byte, baseDir, certDir string) (map[string]map[string]dbus.Variant, []string, error) {
+	vpnData := map[string]string{}
+	var warnings []string
+	var remotes []string
+	proto := ""
+	port := ""
+	userPass := false
+	keyDirection := ""
+	inline := map[string]string{}
+	secrets := map[string]string{}
+
+	// A directory per profile, so importing another client.ovpn can't
+	// overwrite the certificates of an existing one.
+	profileUUID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate uuid: %w", err)
+	}
+	profileDir := filepath.Join(certDir, profileUUID.String())
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		// Inline <block> ... </block>
+		if strings.HasPrefix(line, "<") && strings.HasSuffix(line, ">") && !strings.HasPrefix(line, "</") {
+			tag := strings.Trim(line, "<>")
+			var block strings.Builder
+			closed := false
+			for scanner.Scan() {
+				lineNo++
+				l := scanner.Text()
+				if strings.TrimSpace(l) == "</"+tag+">" {
+					closed = true
+					break
+				}
+				block.WriteString(l + "\n")
+			}
+			if !closed {
+				return nil, nil, fmt.Errorf("line %d: <%s> is never closed", lineNo, tag)
+			}
+			if tag == "auth-user-pass" {
+				// Username on the first line, password on the second.
+				creds := strings.Split(strings.TrimRight(block.String(), "\n"), "\n")
+				userPass = true
+				vpnData["username"] = strings.TrimSpace(creds[0])
+				if len(creds) > 1 {
+					secrets["password"] = strings.TrimRight(creds[1], "\r")
+				}
+				continue
+			}
+			if _, ok := ovpnInline[tag]; !ok {
+				warnings = append(warnings, "<"+tag+">")
+				continue
+			}
+			inline[tag] = block.String()
+			continue
+		}
+
+		fields := strings.Fields(line)
+		directive, args := fields[0], fields[1:]
+		arg := func(i int) string {
+			if i < len(args) {
+				return strings.Trim(args[i], `"`)
+			}
+			return ""
+		}
+
+		switch directive {
+		case "remote":
+			if len(args) == 0 {
+				return nil, nil, fmt.Errorf("line %d: remote needs a host", lineNo)
+			}
+			remote := arg(0)
+			if arg(1) != "" {
+				remote += ":" + arg(1)
+			}
+			if arg(2) != "" {
+				remote += ":" + arg(2)
+			}
+			remotes = append(remotes, remote)
+		case "port", "rport":
+			port = arg(0)
+		case "proto":
+			proto = arg(0)
+		case "dev":
+			if strings.HasPrefix(arg(0), "tap") {
+				vpnData["dev-type"] = "tap"
+			} else {
+				vpnData["dev-type"] = "tun"
+			}
+			if arg(0) != "tun" && arg(0) != "tap" {
+				vpnData["dev"] = arg(0)
+			}
+		case "dev-type":
+			vpnData["dev-type"] = arg(0)
+		case "auth-user-pass":
+			userPass = true
+			if arg(0) != "" {
+				warnings = append(warnings, "auth-user-pass file (enter the credentials when connecting)")
+			}
+		case "key-direction":
+			keyDirection = arg(0)
+		case "tls-auth", "tls-crypt", "tls-crypt-v2", "ca", "cert", "key", "secret", "pkcs12":
+			if arg(0) == "[inline]" || arg(0) == "" {
+				if directive == "tls-auth" || directive == "secret" {
+					keyDirection = arg(1)
+				}
+				continue
+			}
+			file := arg(0)
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(baseDir, file)
+			}
+			vpnData[ovpnInline[directive]] = file
+			if directive == "pkcs12" {
+				// The plugin takes the bundle as cert, key and (optionally) CA.
+				vpnData["key"] = file
+				if _, ok := vpnData["ca"]; !ok {
+					vpnData["ca"] = file
+				}
+			}
+			if (directive == "tls-auth" || directive == "secret") && arg(1) != "" {
+				keyDirection = arg(1)
+			}
+		case "comp-lzo":
+			value := arg(0)
+			if value == "" {
+				value = "yes"
+			}
+			vpnData["comp-lzo"] = value
+		case "float":
+			vpnData["float"] = "yes"
+		case "remote-random":
+			vpnData["remote-random"] = "yes"
+		case "verify-x509-name":
+			kind := arg(1)
+			if kind == "" {
+				kind = "subject"
+			}
+			vpnData["verify-x509-name"] = kind + ":" + arg(0)
+		case "http-proxy", "socks-proxy":
+			vpnData["proxy-type"] = strings.TrimSuffix(directive, "-proxy")
+			vpnData["proxy-server"] = arg(0)
+			vpnData["proxy-port"] = arg(1)
+		default:
+			if key, ok := ovpnDataKeys[directive]; ok {
+				value := strings.Join(args, " ")
+				if value == "" {
+					value = "yes"
+				}
+				vpnData[key] = value
+			} else if !ovpnImplied[directive] {
+				warnings = append(warnings, directive)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if len(remotes) == 0 {
+		return nil, nil, fmt.Errorf("config has no 'remote' directive")
+	}
+
+	// Write inline blocks out to the per-profile directory.
+	if len(inline) > 0 {
+		if err := os.MkdirAll(profileDir, 0700); err != nil {
+			return nil, nil, fmt.Errorf("failed to create %s: %w", profileDir, err)
+		}
+	}
+	for tag, content := range inline {
+		ext := ".pem"
+		raw := []byte(content)
+		switch tag {
+		case "tls-auth", "tls-crypt", "tls-crypt-v2", "secret":
+			ext = ".key"
+		case "pkcs12":
+			// Inline PKCS#12 bundles are base64 encoded.
+			decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(content), ""))
+			if err != nil {
+				removeInlineFiles(vpnData, profileDir)
+				return nil, nil, fmt.Errorf("<pkcs12> is not valid base64: %w", err)
+			}
+			ext, raw = ".p12", decoded
+		}
+		file := filepath.Join(profileDir, tag+ext)
+		if err := os.WriteFile(file, raw, 0600); err != nil {
+			removeInlineFiles(vpnData, profileDir)
+			return nil, nil, fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		vpnData[ovpnInline[tag]] = file
+		if tag == "pkcs12" {
+			vpnData["key"] = file
+			if _, ok := vpnData["ca"]; !ok {
+				vpnData["ca"] = file
+			}
+		}
+	}
+	if _, ok := vpnData["ta"]; ok && keyDirection != "" {
+		vpnData["ta-dir"] = keyDirection
+	}
+	if _, ok := vpnData["static-key"]; ok && keyDirection != "" {
+		vpnData["static-key-direction"] = keyDirection
+	}
+
+	if port != "" {
+		for i, r := range remotes {
+			if !strings.Contains(r, ":") {
+				remotes[i] = r + ":" + port
+			}
+		}
+	}
+	vpnData["remote"] = strings.Join(remotes, ", ")
+	if strings.HasPrefix(proto, "tcp") {
+		vpnData["proto-tcp"] = "yes"
+	}
+
+	// Pick the connection type from what the config authenticates with.
+	_, hasCert := vpnData["cert"]
+	_, hasStatic := vpnData["static-key"]
+	switch {
+	case hasStatic:
+		vpnData["connection-type"] = "static-key"
+	case userPass && hasCert:
+		vpnData["connection-type"] = "password-tls"
+	case userPass:
+		vpnData["connection-type"] = "password"
+	default:
+		vpnData["connection-type"] = "tls"
+	}
+	if _, ok := secrets["password"]; ok {
+		// The config came with the password, so save it like NetworkManager would.
+		vpnData["password-flags"] = "0"
+	} else if userPass {
+		// Ask for the password on every connect rather than storing it.
+		vpnData["password-flags"] = "2"
+	}
+
+	settings := map[string]map[string]dbus.Variant{
+		"connection": {
+			"id":          dbus.MakeVariant(name),
+			"uuid":        dbus.MakeVariant(profileUUID.String()),
+			"type":        dbus.MakeVariant("vpn"),
+			"autoconnect": dbus.MakeVariant(false),
+		},
+		"vpn": {
+			"service-type": dbus.MakeVariant(OpenVPNService),
+			"data":         dbus.MakeVariant(vpnData),
+		},
+		"ipv4": {"method": dbus.MakeVariant("auto")},
+		"ipv6": {"method": dbus.MakeVariant("auto")},
+	}
+	if len(secrets) > 0 {
+		settings["vpn"]["secrets"] = dbus.MakeVariant(secrets)
+	}
+	return settings, warnings, nil
+}
+
+// RemoveOvpnFiles deletes the inline certificates ParseOvpn wrote for a
+// profile that could not be added after all.
+func RemoveOvpnFiles(settings map[string]map[string]dbus.Variant, certDir string) {
+	id, _ := settings["connection"]["uuid"].Value().(string)
+	if uuid.Validate(id) != nil {
+		return
+	}
+	vpnData, _ := settings["vpn"]["data"].Value().(map[string]string)
+	removeInlineFiles(vpnData, filepath.Join(certDir, id))
+}
+
+// removeInlineFiles deletes the files under profileDir that vpnData points
+// to, and the directory itself once it is empty.
+func removeInlineFiles(vpnData map[string]string, profileDir string) {
+	for _, file := range vpnData {
+		if filepath.Dir(file) == profileDir {
+			os.Remove(file)
+		}
+	}
+	os.Remove(profileDir)
+}
@@ -0,0 +1,92 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"netpala/common"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PresetsDir holds user EAP presets: keyfiles with the same sections and keys
+// as common/eap.nmconnection, minus the credentials.
+func PresetsDir() string {
+	return filepath.Join(common.ConfigDir(), "presets")
+}
+
+// LoadEapPresets returns the user presets followed by the built-in ones.
+// Files that can't be read are reported in the error but don't stop the rest
+// from loading.
+func LoadEapPresets() ([]common.EapPreset, error) {
+	var presets []common.EapPreset
+	var errs []error
+
+	files, _ := filepath.Glob(filepath.Join(PresetsDir(), "*.nmconnection"))
+	sort.Strings(files)
+	for _, file := range files {
+		preset, err := loadEapPreset(file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("preset %s: %w", filepath.Base(file), err))
+			continue
+		}
+		presets = append(presets, preset)
+	}
+
+	presets = append(presets, common.BuiltinEapPresets...)
+	return presets, errors.Join(errs...)
+}
+
+func loadEapPreset(file string) (common.EapPreset, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return common.EapPreset{}, err
+	}
+	settings, err := ParseKeyfile(data)
+	if err != nil {
+		return common.EapPreset{}, err
+	}
+
+	ssid, _ := settings["802-11-wireless"]["ssid"].Value().([]byte)
+	if len(ssid) == 0 {
+		return common.EapPreset{}, fmt.Errorf("no ssid in [wifi]")
+	}
+	eap, ok := settings["802-1x"]
+	if !ok {
+		return common.EapPreset{}, fmt.Errorf("no [802-1x] section")
+	}
+
+	str := func(key string) string {
+		v, _ := eap[key].Value().(string)
+		return v
+	}
+	preset := common.EapPreset{
+		Name:         strings.TrimSuffix(filepath.Base(file), ".nmconnection"),
+		SSID:         string(ssid),
+		Phase2:       strings.ToUpper(str("phase2-auth")),
+		AnonIdentity: str("anonymous-identity"),
+		DomainMatch:  str("domain-suffix-match"),
+		PeapVersion:  str("phase1-peapver"),
+	}
+	if id, ok := settings["connection"]["id"].Value().(string); ok && id != "" {
+		preset.Name = id
+	}
+	if methods, ok := eap["eap"].Value().([]string); ok && len(methods) > 0 {
+		preset.Eap = strings.ToUpper(methods[0])
+	}
+	if cert, ok := eap["ca-cert"].Value().([]byte); ok {
+		preset.CaCert = strings.TrimPrefix(strings.TrimRight(string(cert), "\x00"), "file://")
+	}
+	preset.SystemCA, _ = eap["system-ca-certs"].Value().(bool)
+	if matches, ok := eap["altsubject-matches"].Value().([]string); ok {
+		preset.AltSubject = strings.Join(matches, ", ")
+	}
+
+	switch preset.Eap {
+	case "PEAP", "TTLS", "TLS", "PWD":
+	default:
+		return common.EapPreset{}, fmt.Errorf("unsupported eap method '%s'", preset.Eap)
+	}
+	return preset, nil
+}
@@ -0,0 +1,54 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"netpala/common"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	bluezDest    = "org.bluez"
+	bluezAdapter = "org.bluez.Adapter1"
+)
+
+// GetRadioState reads the software state of the Wi-Fi, mobile broadband and
+// Bluetooth radios. Bluetooth is left empty when BlueZ isn't running.
+func GetRadioState(c *dbus.Conn) common.RadioState {
+	p := GetProps(c.Object(NMDest, NMPath), NMDest)
+	state := common.RadioState{Bluetooth: map[dbus.ObjectPath]bool{}}
+	state.Wireless, _ = p["WirelessEnabled"].Value().(bool)
+	state.Wwan, _ = p["WwanEnabled"].Value().(bool)
+
+	var objects map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	err := c.Object(bluezDest, "/").Call("org.freedesktop.DBus.ObjectManager.GetManagedObjects", 0).Store(&objects)
+	if err != nil {
+		return state
+	}
+	for path, ifaces := range objects {
+		if adapter, ok := ifaces[bluezAdapter]; ok {
+			state.Bluetooth[path], _ = adapter["Powered"].Value().(bool)
+		}
+	}
+	return state
+}
+
+// SetRadioState switches every radio in state on or off. All of them are
+// tried even when one fails.
+func SetRadioState(c *dbus.Conn, state common.RadioState) error {
+	var errs []error
+	nm := c.Object(NMDest, NMPath)
+	if err := nm.SetProperty(NMDest+".WirelessEnabled", dbus.MakeVariant(state.Wireless)); err != nil {
+		errs = append(errs, fmt.Errorf("Wi-Fi: %w", err))
+	}
+	if err := nm.SetProperty(NMDest+".WwanEnabled", dbus.MakeVariant(state.Wwan)); err != nil {
+		errs = append(errs, fmt.Errorf("mobile broadband: %w", err))
+	}
+	for path, on := range state.Bluetooth {
+		if err := c.Object(bluezDest, path).SetProperty(bluezAdapter+".Powered", dbus.MakeVariant(on)); err != nil {
+			errs = append(errs, fmt.Errorf("bluetooth: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
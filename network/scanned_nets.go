@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"netpala/common"
 	"sort"
-	"strings"
 
 	"github.com/godbus/dbus/v5"
 )
@@ -36,82 +35,21 @@ func GetScannedNetworks(c *dbus.Conn) []common.ScannedNetwork {
 		}
 
 		for _, apPath := range apPaths {
-			apObj := c.Object(NMDest, apPath)
-			apProps := GetProps(apObj, AccessPointIF)
-
-			var ssid string
-			if ssidVal, ok := apProps["Ssid"]; ok {
-				if ssidBytes, ok := ssidVal.Value().([]byte); ok {
-					ssid = strings.TrimRight(string(ssidBytes), "\x00")
-				}
-			}
-			if ssid == "" {
+			ap := readAccessPoint(c, apPath)
+			if ap.SSID == "" {
 				continue
 			}
-
-			bssid := ""
-			if bssidVal, ok := apProps["HwAddress"]; ok {
-				bssid = bssidVal.Value().(string)
-			}
-			var signal int
-			if strengthVal, ok := apProps["Strength"]; ok {
-				signal = int(strengthVal.Value().(byte))
-			}
-
-			ap := c.Object(NMDest, apPath)
-			wpaFlagsVar, _ := ap.GetProperty("org.freedesktop.NetworkManager.AccessPoint.WpaFlags")
-			rsnFlagsVar, _ := ap.GetProperty("org.freedesktop.NetworkManager.AccessPoint.RsnFlags")
-			var wpaFlags, rsnFlags uint32
-			if val, ok := wpaFlagsVar.Value().(uint32); ok {
-				wpaFlags = val
-			}
-			if val, ok := rsnFlagsVar.Value().(uint32); ok {
-				rsnFlags = val
-			}
-
 			allNetworks = append(allNetworks, common.ScannedNetwork{
-				SSID:     ssid,
-				BSSID:    bssid,
-				Security: getSecurityType(wpaFlags, rsnFlags),
-				Signal:   signal,
+				SSID:     ap.SSID,
+				BSSID:    ap.BSSID,
+				Security: ap.Security,
+				Signal:   ap.Signal,
 			})
 		}
 	}
 	return removeDuplicates(allNetworks)
 }
 
-func getSecurityType(wpaFlags, rsnFlags uint32) string {
-	const (
-		NM_802_11_AP_SEC_KEY_MGMT_PSK             = 0x00000100
-		NM_802_11_AP_SEC_KEY_MGMT_802_1X          = 0x00000200
-		NM_802_11_AP_SEC_KEY_MGMT_SAE             = 0x00000400
-		NM_802_11_AP_SEC_KEY_MGMT_OWE             = 0x00000800
-		NM_802_11_AP_SEC_KEY_MGMT_OWE_TM          = 0x00001000
-		NM_802_11_AP_SEC_KEY_MGMT_EAP_SUITE_B_192 = 0x00002000
-	)
-
-	var security []string
-	if rsnFlags&NM_802_11_AP_SEC_KEY_MGMT_SAE != 0 {
-		security = append(security, "wpa3-sae")
-	}
-	if rsnFlags&NM_802_11_AP_SEC_KEY_MGMT_EAP_SUITE_B_192 != 0 {
-		security = append(security, "wpa3-eap-192")
-	}
-	if (rsnFlags&NM_802_11_AP_SEC_KEY_MGMT_802_1X != 0) || (wpaFlags&NM_802_11_AP_SEC_KEY_MGMT_802_1X != 0) {
-		security = append(security, "wpa2-eap")
-	}
-	if (rsnFlags&NM_802_11_AP_SEC_KEY_MGMT_PSK != 0) || (wpaFlags&NM_802_11_AP_SEC_KEY_MGMT_PSK != 0) {
-		security = append(security, "wpa2-psk")
-	}
-	if rsnFlags&(NM_802_11_AP_SEC_KEY_MGMT_OWE|NM_802_11_AP_SEC_KEY_MGMT_OWE_TM) != 0 {
-		security = append(security, "wpa-owe")
-	}
-	if len(security) == 0 {
-		return "open"
-	}
-	return strings.Join(security, " / ")
-}
-
 func removeDuplicates(networks []common.ScannedNetwork) []common.ScannedNetwork {
 	networkMap := make(map[string]common.ScannedNetwork)
 	for _, network := range networks {
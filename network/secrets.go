@@ -0,0 +1,152 @@
+package network
+
+import (
+	"fmt"
+	"netpala/common"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// GetSecrets asks NetworkManager (and any registered secret agent) for the
+// secrets of a single setting, e.g. "802-11-wireless-security".
+func GetSecrets(c *dbus.Conn, path dbus.ObjectPath, setting string) (map[string]map[string]dbus.Variant, error) {
+	var secrets map[string]map[string]dbus.Variant
+	err := c.Object(NMDest, path).
+		Call("org.freedesktop.NetworkManager.Settings.Connection.GetSecrets", 0, setting).
+		Store(&secrets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s secrets: %w", setting, err)
+	}
+	return secrets, nil
+}
+
+// FindConnectionBySSID returns the first saved Wi-Fi profile for an SSID.
+func FindConnectionBySSID(c *dbus.Conn, ssid string) (dbus.ObjectPath, map[string]map[string]dbus.Variant, error) {
+	settingsObj := c.Object(NMDest, "/org/freedesktop/NetworkManager/Settings")
+	var connPaths []dbus.ObjectPath
+	if err := settingsObj.Call("org.freedesktop.NetworkManager.Settings.ListConnections", 0).Store(&connPaths); err != nil {
+		return "/", nil, fmt.Errorf("failed to list connections: %w", err)
+	}
+
+	for _, path := range connPaths {
+		var settings map[string]map[string]dbus.Variant
+		if c.Object(NMDest, path).Call("org.freedesktop.NetworkManager.Settings.Connection.GetSettings", 0).Store(&settings) != nil {
+			continue
+		}
+		if b, ok := settings["802-11-wireless"]["ssid"].Value().([]byte); ok && strings.TrimRight(string(b), "\x00") == ssid {
+			return path, settings, nil
+		}
+	}
+	return "/", nil, fmt.Errorf("no saved network named '%s'", ssid)
+}
+
+// ConnectionExists reports whether a saved profile has the given UUID.
+func ConnectionExists(c *dbus.Conn, uuid string) bool {
+	var path dbus.ObjectPath
+	err := c.Object(NMDest, "/org/freedesktop/NetworkManager/Settings").
+		Call("org.freedesktop.NetworkManager.Settings.GetConnectionByUuid", 0, uuid).
+		Store(&path)
+	return err == nil
+}
+
+// FindConnectionByID returns the saved profile with the given name.
+func FindConnectionByID(c *dbus.Conn, id string) (dbus.ObjectPath, error) {
+	settingsObj := c.Object(NMDest, "/org/freedesktop/NetworkManager/Settings")
+	var connPaths []dbus.ObjectPath
+	if err := settingsObj.Call("org.freedesktop.NetworkManager.Settings.ListConnections", 0).Store(&connPaths); err != nil {
+		return "/", fmt.Errorf("failed to list connections: %w", err)
+	}
+
+	for _, path := range connPaths {
+		var settings map[string]map[string]dbus.Variant
+		if c.Object(NMDest, path).Call("org.freedesktop.NetworkManager.Settings.Connection.GetSettings", 0).Store(&settings) != nil {
+			continue
+		}
+		if name, _ := settings["connection"]["id"].Value().(string); name == id {
+			return path, nil
+		}
+	}
+	return "/", fmt.Errorf("no saved connection named '%s'", id)
+}
+
+// GetWifiQRPayload builds the WIFI: QR payload for a saved network.
+func GetWifiQRPayload(c *dbus.Conn, path dbus.ObjectPath) (string, string, error) {
+	var settings map[string]map[string]dbus.Variant
+	if err := c.Object(NMDest, path).Call("org.freedesktop.NetworkManager.Settings.Connection.GetSettings", 0).Store(&settings); err != nil {
+		return "", "", fmt.Errorf("failed to read connection: %w", err)
+	}
+
+	ssid := ""
+	if b, ok := settings["802-11-wireless"]["ssid"].Value().([]byte); ok {
+		ssid = strings.TrimRight(string(b), "\x00")
+	}
+	hidden, _ := settings["802-11-wireless"]["hidden"].Value().(bool)
+	keyMgmt, _ := settings["802-11-wireless-security"]["key-mgmt"].Value().(string)
+
+	password := ""
+	if keyMgmt != "" && keyMgmt != "owe" {
+		secrets, err := GetSecrets(c, path, "802-11-wireless-security")
+		if err != nil {
+			return "", "", err
+		}
+		wsec := secrets["802-11-wireless-security"]
+		if psk, ok := wsec["psk"].Value().(string); ok {
+			password = psk
+		} else if key, ok := wsec["wep-key0"].Value().(string); ok {
+			password = key
+		}
+	}
+
+	payload, err := common.WifiQRString(ssid, keyMgmt, password, hidden)
+	return ssid, payload, err
+}
+
+// GetSavedSecret returns the secret a known network authenticates with,
+// together with a label describing what kind of secret it is.
+func GetSavedSecret(c *dbus.Conn, path dbus.ObjectPath) (string, string, error) {
+	var settings map[string]map[string]dbus.Variant
+	if err := c.Object(NMDest, path).Call("org.freedesktop.NetworkManager.Settings.Connection.GetSettings", 0).Store(&settings); err != nil {
+		return "", "", fmt.Errorf("failed to read connection: %w", err)
+	}
+
+	keyMgmt, _ := settings["802-11-wireless-security"]["key-mgmt"].Value().(string)
+	switch keyMgmt {
+	case "":
+		return "", "", fmt.Errorf("open networks have no saved password")
+	case "owe":
+		return "", "", fmt.Errorf("OWE networks have no saved password")
+	case "wpa-eap", "wpa-eap-suite-b-192", "ieee8021x":
+		// Dynamic WEP keeps its credentials in 802-1x as well
+		secrets, err := GetSecrets(c, path, "802-1x")
+		if err != nil {
+			return "", "", err
+		}
+		if pw, ok := secrets["802-1x"]["password"].Value().(string); ok && pw != "" {
+			return "802.1X password", pw, nil
+		}
+		if pw, ok := secrets["802-1x"]["private-key-password"].Value().(string); ok && pw != "" {
+			return "private key password", pw, nil
+		}
+		return "", "", fmt.Errorf("no 802.1X password is stored for this network")
+	case "none":
+		secrets, err := GetSecrets(c, path, "802-11-wireless-security")
+		if err != nil {
+			return "", "", err
+		}
+		idx, _ := settings["802-11-wireless-security"]["wep-tx-keyidx"].Value().(uint32)
+		if key, ok := secrets["802-11-wireless-security"][fmt.Sprintf("wep-key%d", idx)].Value().(string); ok && key != "" {
+			return "WEP key", key, nil
+		}
+		return "", "", fmt.Errorf("no WEP key is stored for this network")
+	default:
+		secrets, err := GetSecrets(c, path, "802-11-wireless-security")
+		if err != nil {
+			return "", "", err
+		}
+		if psk, ok := secrets["802-11-wireless-security"]["psk"].Value().(string); ok && psk != "" {
+			return "password", psk, nil
+		}
+		return "", "", fmt.Errorf("no password is stored for this network")
+	}
+}
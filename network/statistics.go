@@ -0,0 +1,33 @@
+package network
+
+import (
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// StatisticsRefreshMs is how often NetworkManager is asked to update the
+// byte counters of the active device.
+const StatisticsRefreshMs uint32 = 1000
+
+// SetStatisticsRefreshRate changes how often a device's byte counters are
+// refreshed and returns the previous rate. 0 stops the updates.
+func SetStatisticsRefreshRate(c *dbus.Conn, path dbus.ObjectPath, rateMs uint32) (uint32, error) {
+	obj := c.Object(NMDest, path)
+	var prev uint32
+	if v, err := obj.GetProperty(DevStatsIF + ".RefreshRateMs"); err == nil {
+		prev, _ = v.Value().(uint32)
+	}
+	if err := obj.SetProperty(DevStatsIF+".RefreshRateMs", dbus.MakeVariant(rateMs)); err != nil {
+		return prev, fmt.Errorf("failed to enable device statistics: %w", err)
+	}
+	return prev, nil
+}
+
+// GetStatistics reads the bytes received and sent by a device.
+func GetStatistics(c *dbus.Conn, path dbus.ObjectPath) (rx, tx uint64) {
+	props := GetProps(c.Object(NMDest, path), DevStatsIF)
+	rx, _ = props["RxBytes"].Value().(uint64)
+	tx, _ = props["TxBytes"].Value().(uint64)
+	return rx, tx
+}
@@ -0,0 +1,342 @@
+package network
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"netpala/common"
+	"strconv"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// ParseWgQuick converts a wg-quick style .conf file into a NetworkManager
+// "wireguard" connection. The interface name is used as connection id and
+// interface-name, like `nmcli connection import type wireguard` does.
+// Directives NetworkManager has no equivalent for are returned as warnings.
+func ParseWgQuick(iface string, data []byte) (map[string]map[string]dbus.Variant, []string, error) {
+	if len(iface) == 0 || len(iface) > 15 {
+		return nil, nil, fmt.Errorf("'%s' is not a valid interface name (1-15 characters)", iface)
+	}
+
+	wg := map[string]dbus.Variant{
+		"private-key-flags": dbus.MakeVariant(uint32(0)),
+	}
+	ipv4 := map[string]dbus.Variant{}
+	ipv6 := map[string]dbus.Variant{}
+	var addr4, addr6 []map[string]dbus.Variant
+	var dns4 []uint32
+	var dns6 [][]byte
+	var dnsSearch []string
+	var peers []map[string]dbus.Variant
+	var warnings []string
+
+	section := ""
+	var peer map[string]dbus.Variant
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+			switch section {
+			case "interface":
+			case "peer":
+				peer = map[string]dbus.Variant{}
+				peers = append(peers, peer)
+			default:
+				return nil, nil, fmt.Errorf("line %d: unknown section [%s]", lineNo, section)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || section == "" {
+			return nil, nil, fmt.Errorf("line %d: expected Key = Value inside a section", lineNo)
+		}
+		rawKey := strings.TrimSpace(key)
+		key, value = strings.ToLower(rawKey), strings.TrimSpace(value)
+		fail := func(err error) error { return fmt.Errorf("line %d: %s: %w", lineNo, rawKey, err) }
+
+		if section == "interface" {
+			switch key {
+			case "privatekey":
+				wg["private-key"] = dbus.MakeVariant(value)
+			case "listenport":
+				port, err := strconv.ParseUint(value, 10, 16)
+				if err != nil {
+					return nil, nil, fail(err)
+				}
+				wg["listen-port"] = dbus.MakeVariant(uint32(port))
+			case "fwmark":
+				if value == "off" {
+					continue
+				}
+				mark, err := strconv.ParseUint(value, 0, 32)
+				if err != nil {
+					return nil, nil, fail(err)
+				}
+				wg["fwmark"] = dbus.MakeVariant(uint32(mark))
+			case "mtu":
+				mtu, err := strconv.ParseUint(value, 10, 32)
+				if err != nil {
+					return nil, nil, fail(err)
+				}
+				wg["mtu"] = dbus.MakeVariant(uint32(mtu))
+			case "address":
+				for _, a := range splitComma(value) {
+					ip, ipNet, err := net.ParseCIDR(a)
+					if err != nil {
+						// wg-quick allows bare addresses and assumes a host prefix.
+						if ip = net.ParseIP(a); ip == nil {
+							return nil, nil, fail(err)
+						}
+						bits := 128
+						if ip.To4() != nil {
+							bits = 32
+						}
+						ipNet = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+					}
+					prefix, _ := ipNet.Mask.Size()
+					entry := map[string]dbus.Variant{
+						"address": dbus.MakeVariant(ip.String()),
+						"prefix":  dbus.MakeVariant(uint32(prefix)),
+					}
+					if ip.To4() != nil {
+						addr4 = append(addr4, entry)
+					} else {
+						addr6 = append(addr6, entry)
+					}
+				}
+			case "dns":
+				for _, d := range splitComma(value) {
+					ip := net.ParseIP(d)
+					switch {
+					case ip == nil:
+						dnsSearch = append(dnsSearch, d)
+					case ip.To4() != nil:
+						dns4 = append(dns4, binary.LittleEndian.Uint32(ip.To4()))
+					default:
+						dns6 = append(dns6, []byte(ip.To16()))
+					}
+				}
+			default:
+				// Table, PreUp, PostUp, PreDown, PostDown, SaveConfig
+				warnings = append(warnings, "[Interface] "+rawKey)
+			}
+			continue
+		}
+
+		switch key {
+		case "publickey":
+			peer["public-key"] = dbus.MakeVariant(value)
+		case "presharedkey":
+			peer["preshared-key"] = dbus.MakeVariant(value)
+			peer["preshared-key-flags"] = dbus.MakeVariant(uint32(0))
+		case "endpoint":
+			peer["endpoint"] = dbus.MakeVariant(value)
+		case "allowedips":
+			peer["allowed-ips"] = dbus.MakeVariant(splitComma(value))
+		case "persistentkeepalive":
+			if value == "off" {
+				continue
+			}
+			keepalive, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return nil, nil, fail(err)
+			}
+			peer["persistent-keepalive"] = dbus.MakeVariant(uint32(keepalive))
+		default:
+			warnings = append(warnings, "[Peer] "+rawKey)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if _, ok := wg["private-key"]; !ok {
+		return nil, nil, fmt.Errorf("[Interface] has no PrivateKey")
+	}
+	for i, p := range peers {
+		if _, ok := p["public-key"]; !ok {
+			return nil, nil, fmt.Errorf("[Peer] #%d has no PublicKey", i+1)
+		}
+	}
+	if len(peers) > 0 {
+		wg["peers"] = dbus.MakeVariant(peers)
+	}
+
+	ipv4["method"] = dbus.MakeVariant("disabled")
+	if len(addr4) > 0 {
+		ipv4["method"] = dbus.MakeVariant("manual")
+		ipv4["address-data"] = dbus.MakeVariant(addr4)
+	}
+	ipv6["method"] = dbus.MakeVariant("disabled")
+	if len(addr6) > 0 {
+		ipv6["method"] = dbus.MakeVariant("manual")
+		ipv6["address-data"] = dbus.MakeVariant(addr6)
+	}
+	if len(dns4) > 0 {
+		ipv4["dns"] = dbus.MakeVariant(dns4)
+	}
+	if len(dns6) > 0 {
+		ipv6["dns"] = dbus.MakeVariant(dns6)
+	}
+	if len(dnsSearch) > 0 {
+		ipv4["dns-search"] = dbus.MakeVariant(dnsSearch)
+	}
+	if len(dns4) > 0 || len(dns6) > 0 {
+		// Route all DNS through the tunnel, like wg-quick does with resolvconf.
+		ipv4["dns-priority"] = dbus.MakeVariant(int32(-50))
+		ipv6["dns-priority"] = dbus.MakeVariant(int32(-50))
+	}
+
+	settings := map[string]map[string]dbus.Variant{
+		"connection": {
+			"id":             dbus.MakeVariant(iface),
+			"type":           dbus.MakeVariant("wireguard"),
+			"interface-name": dbus.MakeVariant(iface),
+			"autoconnect":    dbus.MakeVariant(false),
+		},
+		"wireguard": wg,
+		"ipv4":      ipv4,
+		"ipv6":      ipv6,
+	}
+	return settings, warnings, nil
+}
+
+func splitComma(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// GetWireGuardConfig reads the wireguard setting of a saved profile,
+// including its private and preshared keys.
+func GetWireGuardConfig(c *dbus.Conn, path dbus.ObjectPath) (string, common.WireGuardConfig, error) {
+	var settings map[string]map[string]dbus.Variant
+	if err := c.Object(NMDest, path).Call("org.freedesktop.NetworkManager.Settings.Connection.GetSettings", 0).Store(&settings); err != nil {
+		return "", common.WireGuardConfig{}, fmt.Errorf("failed to read connection: %w", err)
+	}
+	wg, ok := settings["wireguard"]
+	if !ok {
+		return "", common.WireGuardConfig{}, fmt.Errorf("not a WireGuard connection")
+	}
+	if secrets, err := GetSecrets(c, path, "wireguard"); err == nil {
+		for k, v := range secrets["wireguard"] {
+			if k == "peers" {
+				mergePeerSecrets(wg, v)
+				continue
+			}
+			wg[k] = v
+		}
+	}
+
+	name, _ := settings["connection"]["id"].Value().(string)
+	cfg := common.WireGuardConfig{}
+	cfg.PrivateKey, _ = wg["private-key"].Value().(string)
+	cfg.ListenPort, _ = wg["listen-port"].Value().(uint32)
+	cfg.FwMark, _ = wg["fwmark"].Value().(uint32)
+	cfg.MTU, _ = wg["mtu"].Value().(uint32)
+
+	peers, _ := wg["peers"].Value().([]map[string]dbus.Variant)
+	for _, p := range peers {
+		peer := common.WireGuardPeer{}
+		peer.PublicKey, _ = p["public-key"].Value().(string)
+		peer.PresharedKey, _ = p["preshared-key"].Value().(string)
+		peer.Endpoint, _ = p["endpoint"].Value().(string)
+		peer.AllowedIPs, _ = p["allowed-ips"].Value().([]string)
+		peer.Keepalive, _ = p["persistent-keepalive"].Value().(uint32)
+		cfg.Peers = append(cfg.Peers, peer)
+	}
+	return name, cfg, nil
+}
+
+// ValidateWireGuardConfig checks the keys, endpoints and allowed IPs of a
+// config before it is sent to NetworkManager.
+func ValidateWireGuardConfig(cfg common.WireGuardConfig) error {
+	if err := common.ValidateWireGuardKey(cfg.PrivateKey); err != nil {
+		return fmt.Errorf("private key: %w", err)
+	}
+	if cfg.ListenPort > 65535 {
+		return fmt.Errorf("listen port %d is out of range", cfg.ListenPort)
+	}
+	for i, p := range cfg.Peers {
+		if err := common.ValidateWireGuardKey(p.PublicKey); err != nil {
+			return fmt.Errorf("peer %d public key: %w", i+1, err)
+		}
+		for _, ip := range p.AllowedIPs {
+			if _, _, err := net.ParseCIDR(ip); err != nil && net.ParseIP(ip) == nil {
+				return fmt.Errorf("peer %d allowed IP '%s' is not an address or CIDR", i+1, ip)
+			}
+		}
+		if p.Endpoint != "" {
+			if _, _, err := net.SplitHostPort(p.Endpoint); err != nil {
+				return fmt.Errorf("peer %d endpoint: %w", i+1, err)
+			}
+		}
+		if p.PresharedKey != "" {
+			if err := common.ValidateWireGuardKey(p.PresharedKey); err != nil {
+				return fmt.Errorf("peer %d preshared key: %w", i+1, err)
+			}
+		}
+		if p.Keepalive > 65535 {
+			return fmt.Errorf("peer %d keepalive %d is out of range", i+1, p.Keepalive)
+		}
+	}
+	return nil
+}
+
+// WireGuardSetting builds the D-Bus "wireguard" setting for a config.
+func WireGuardSetting(cfg common.WireGuardConfig) (map[string]dbus.Variant, error) {
+	if err := ValidateWireGuardConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	wg := map[string]dbus.Variant{
+		"private-key":       dbus.MakeVariant(cfg.PrivateKey),
+		"private-key-flags": dbus.MakeVariant(uint32(0)),
+		"listen-port":       dbus.MakeVariant(cfg.ListenPort),
+		"fwmark":            dbus.MakeVariant(cfg.FwMark),
+		"mtu":               dbus.MakeVariant(cfg.MTU),
+	}
+
+	var peers []map[string]dbus.Variant
+	for _, p := range cfg.Peers {
+		peer := map[string]dbus.Variant{
+			"public-key":           dbus.MakeVariant(p.PublicKey),
+			"allowed-ips":          dbus.MakeVariant(p.AllowedIPs),
+			"persistent-keepalive": dbus.MakeVariant(p.Keepalive),
+		}
+		if p.AllowedIPs == nil {
+			peer["allowed-ips"] = dbus.MakeVariant([]string{})
+		}
+		if p.Endpoint != "" {
+			peer["endpoint"] = dbus.MakeVariant(p.Endpoint)
+		}
+		if p.PresharedKey != "" {
+			peer["preshared-key"] = dbus.MakeVariant(p.PresharedKey)
+			peer["preshared-key-flags"] = dbus.MakeVariant(uint32(0))
+		}
+		peers = append(peers, peer)
+	}
+	if peers == nil {
+		peers = []map[string]dbus.Variant{}
+	}
+	wg["peers"] = dbus.MakeVariant(peers)
+	return wg, nil
+}
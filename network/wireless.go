@@ -0,0 +1,123 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"netpala/common"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Keyword values of 802-11-wireless.assigned-mac-address.
+var macKeywords = []string{"permanent", "random", "stable", "preserve"}
+
+// GetWirelessConfig reads the advanced Wi-Fi settings of a saved profile.
+func GetWirelessConfig(c *dbus.Conn, path dbus.ObjectPath) (string, common.WirelessConfig, error) {
+	var settings map[string]map[string]dbus.Variant
+	if err := c.Object(NMDest, path).Call("org.freedesktop.NetworkManager.Settings.Connection.GetSettings", 0).Store(&settings); err != nil {
+		return "", common.WirelessConfig{}, fmt.Errorf("failed to read connection: %w", err)
+	}
+	wifi, ok := settings["802-11-wireless"]
+	if !ok {
+		return "", common.WirelessConfig{}, fmt.Errorf("not a Wi-Fi connection")
+	}
+
+	name, _ := settings["connection"]["id"].Value().(string)
+	cfg := common.WirelessConfig{WakeOnWlan: 1}
+	cfg.Band, _ = wifi["band"].Value().(string)
+	cfg.Channel, _ = wifi["channel"].Value().(uint32)
+	cfg.PowerSave, _ = wifi["powersave"].Value().(uint32)
+	cfg.MTU, _ = wifi["mtu"].Value().(uint32)
+	if wol, ok := wifi["wake-on-wlan"].Value().(uint32); ok {
+		cfg.WakeOnWlan = wol
+	}
+	if mac, ok := wifi["assigned-mac-address"].Value().(string); ok {
+		cfg.ClonedMAC = mac
+	} else if mac, ok := wifi["cloned-mac-address"].Value().([]byte); ok && len(mac) == 6 {
+		cfg.ClonedMAC = net.HardwareAddr(mac).String()
+	}
+	if wsec, ok := settings["802-11-wireless-security"]; ok {
+		cfg.Secured = true
+		cfg.PMF, _ = wsec["pmf"].Value().(int32)
+	}
+	return name, cfg, nil
+}
+
+// ValidateWirelessConfig checks that the settings make sense together.
+func ValidateWirelessConfig(cfg common.WirelessConfig) error {
+	switch cfg.Band {
+	case "":
+		if cfg.Channel != 0 {
+			return fmt.Errorf("a channel needs a band")
+		}
+	case "bg":
+		if cfg.Channel > 14 {
+			return fmt.Errorf("channel %d isn't a 2.4 GHz channel", cfg.Channel)
+		}
+	case "a":
+		if cfg.Channel != 0 && cfg.Channel < 32 {
+			return fmt.Errorf("channel %d isn't a 5 GHz channel", cfg.Channel)
+		}
+	default:
+		return fmt.Errorf("unknown band '%s'", cfg.Band)
+	}
+
+	if mac := strings.ToLower(strings.TrimSpace(cfg.ClonedMAC)); mac != "" && !isMACKeyword(mac) {
+		if hw, err := net.ParseMAC(mac); err != nil || len(hw) != 6 {
+			return fmt.Errorf("invalid MAC address '%s'", cfg.ClonedMAC)
+		}
+	}
+	if cfg.PMF != 0 && !cfg.Secured {
+		return fmt.Errorf("PMF needs a secured network")
+	}
+	return nil
+}
+
+// ApplyWirelessConfig writes cfg into a profile's settings, removing keys
+// that are back at their defaults.
+func ApplyWirelessConfig(settings map[string]map[string]dbus.Variant, cfg common.WirelessConfig) error {
+	if err := ValidateWirelessConfig(cfg); err != nil {
+		return err
+	}
+	mac := strings.ToLower(strings.TrimSpace(cfg.ClonedMAC))
+	if hw, err := net.ParseMAC(mac); err == nil {
+		mac = strings.ToUpper(hw.String())
+	}
+
+	wifi := settings["802-11-wireless"]
+	setOrDelete := func(key string, value any, isDefault bool) {
+		if isDefault {
+			delete(wifi, key)
+		} else {
+			wifi[key] = dbus.MakeVariant(value)
+		}
+	}
+	setOrDelete("band", cfg.Band, cfg.Band == "")
+	setOrDelete("channel", cfg.Channel, cfg.Channel == 0)
+	setOrDelete("powersave", cfg.PowerSave, cfg.PowerSave == 0)
+	setOrDelete("mtu", cfg.MTU, cfg.MTU == 0)
+	setOrDelete("wake-on-wlan", cfg.WakeOnWlan, cfg.WakeOnWlan == 1)
+	// cloned-mac-address is the deprecated byte form of the same setting and
+	// would override the string one.
+	delete(wifi, "cloned-mac-address")
+	setOrDelete("assigned-mac-address", mac, mac == "")
+
+	if wsec, ok := settings["802-11-wireless-security"]; ok {
+		if cfg.PMF == 0 {
+			delete(wsec, "pmf")
+		} else {
+			wsec["pmf"] = dbus.MakeVariant(cfg.PMF)
+		}
+	}
+	return nil
+}
+
+func isMACKeyword(s string) bool {
+	for _, k := range macKeywords {
+		if s == k {
+			return true
+		}
+	}
+	return false
+}